@@ -0,0 +1,138 @@
+package groupme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// addMembersPolling is the backoff between polls of AddMembersResults
+var addMembersPolling = RetryPolicy{MinBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second, Jitter: 0.2}
+
+// AddMemberResult is the outcome of adding one member
+type AddMemberResult struct {
+	// Requested is the member passed to AddMembersAndWait
+	Requested *Member
+	// Membership is the created membership. Nil if the add failed
+	Membership *Member
+}
+
+// Added reports whether the member was added
+func (r AddMemberResult) Added() bool {
+	return r.Membership != nil
+}
+
+// AddMembersReport is the outcome of AddMembersAndWait
+type AddMembersReport struct {
+	ResultsID string
+	// Results in the order of the requested members
+	Results []AddMemberResult
+}
+
+// Added returns the created memberships
+func (r *AddMembersReport) Added() []*Member {
+	var added []*Member
+	for _, result := range r.Results {
+		if result.Added() {
+			added = append(added, result.Membership)
+		}
+	}
+	return added
+}
+
+// Failed returns the requested members that weren't added
+func (r *AddMembersReport) Failed() []*Member {
+	var failed []*Member
+	for _, result := range r.Results {
+		if !result.Added() {
+			failed = append(failed, result.Requested)
+		}
+	}
+	return failed
+}
+
+/*
+AddMembersAndWait -
+
+Adds members to a group, then polls AddMembersResults with backoff until
+every member is accounted for, or the timeout passes. Members without a
+GUID are assigned one, which correlates them with their memberships.
+
+GroupMe omits failed adds from the results, so members still missing when
+the timeout passes are reported as failed. An error is returned only if
+the add failed, or the results never became available
+*/
+func (c *Client) AddMembersAndWait(ctx context.Context, groupID string, timeout time.Duration, members ...*Member) (*AddMembersReport, error) {
+	report := &AddMembersReport{Results: make([]AddMemberResult, len(members))}
+	for i, member := range members {
+		if member.GUID == "" {
+			member.GUID = uuid.New().String()
+		}
+		report.Results[i].Requested = member
+	}
+
+	resultsID, err := c.AddMembers(ctx, groupID, members...)
+	if err != nil {
+		return nil, err
+	}
+	report.ResultsID = resultsID
+
+	deadline := time.Now().Add(timeout)
+	var available bool
+	for polls := 1; ; polls++ {
+		wait := addMembersPolling.backoff(polls)
+		if remaining := time.Until(deadline); wait > remaining {
+			wait = remaining
+		}
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return report, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		memberships, err := c.AddMembersResults(ctx, groupID, resultsID)
+		switch {
+		case err == nil:
+			available = true
+			if report.match(memberships) {
+				return report, nil
+			}
+		// Results are unavailable while the adds are processed
+		case !errors.Is(err, ErrServerError):
+			return report, err
+		}
+
+		if !time.Now().Before(deadline) {
+			if !available {
+				return report, fmt.Errorf("add members results unavailable after %v: %w", timeout, err)
+			}
+			return report, nil
+		}
+	}
+}
+
+// match records the memberships of the requested members, reporting
+// whether every member has one
+func (r *AddMembersReport) match(memberships []*Member) bool {
+	byGUID := make(map[string]*Member, len(memberships))
+	for _, membership := range memberships {
+		byGUID[membership.GUID] = membership
+	}
+
+	complete := true
+	for i := range r.Results {
+		if membership, ok := byGUID[r.Results[i].Requested.GUID]; ok {
+			r.Results[i].Membership = membership
+		} else {
+			complete = false
+		}
+	}
+	return complete
+}
@@ -0,0 +1,48 @@
+package groupme_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddMembersAndWait(t *testing.T) {
+	team := newTeam(t)
+	team.server.PendingAddResults = 1
+
+	bob := &groupme.Member{Nickname: "Bob", UserID: "2"}
+	carol := &groupme.Member{Nickname: "Carol", Email: "carol@example.com", GUID: "carol"}
+	unknown := &groupme.Member{Nickname: "Nobody", UserID: "404"}
+
+	report, err := team.alice.AddMembersAndWait(context.Background(), team.group.ID, 2*time.Second, bob, carol, unknown)
+	require.NoError(t, err)
+	assert.NotEmpty(t, report.ResultsID)
+
+	require.Len(t, report.Results, 3)
+	assert.NotEmpty(t, bob.GUID)
+	assert.Equal(t, "carol", carol.GUID)
+	assert.True(t, report.Results[0].Added())
+	assert.Equal(t, "2", report.Results[0].Membership.UserID)
+	assert.True(t, report.Results[1].Added())
+	assert.Equal(t, "3", report.Results[1].Membership.UserID)
+	assert.False(t, report.Results[2].Added())
+
+	assert.Len(t, report.Added(), 2)
+	assert.Equal(t, []*groupme.Member{unknown}, report.Failed())
+	assert.Len(t, team.server.Group(team.group.ID).Members, 3)
+}
+
+func TestAddMembersAndWait_Unavailable(t *testing.T) {
+	team := newTeam(t)
+	team.server.PendingAddResults = 100
+
+	report, err := team.alice.AddMembersAndWait(context.Background(), team.group.ID, 0, &groupme.Member{Nickname: "Bob", UserID: "2"})
+	assert.True(t, errors.Is(err, groupme.ErrServerError), err)
+	require.NotNil(t, report)
+	assert.Len(t, report.Failed(), 1)
+}
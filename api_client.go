@@ -6,7 +6,9 @@ import (
 )
 
 // Client communicates with the GroupMe API to perform actions
-// on the basic types, i.e. Listing, Creating, Destroying
+// on the basic types, i.e. Listing, Creating, Destroying.
+// It also uploads pictures with UploadPictureReader, UploadPictureBytes,
+// UploadPictureFile and UploadPictureURL
 type Client struct {
 	client
 	authorizationToken string
@@ -14,20 +16,14 @@ type Client struct {
 
 // NewClient creates a new GroupMe API Client
 func NewClient(authToken string, options ...ClientOption) *Client {
-	client := &Client{
-		client: client{
-			httpClient:        &http.Client{},
-			apiEndpointBase:   GroupMeAPIBase,
-			imageEndpointBase: GroupMeImageBase,
-		},
+	c := &Client{
+		client:             newClient(options),
 		authorizationToken: authToken,
 	}
-
-	for _, option := range options {
-		option(&client.client)
+	if c.imageToken == "" {
+		c.imageToken = authToken
 	}
-
-	return client
+	return c
 }
 
 func (c Client) doWithAuthToken(ctx context.Context, req *http.Request, i interface{}) error {
@@ -53,6 +53,19 @@ func (s *ClientSuite) TestClient_do_UnmarshalError() {
 	s.Assert().Error(s.client.do(context.Background(), req, struct{}{}))
 }
 
+func (s *ClientSuite) TestClient_BaseURLOptions() {
+	client := NewClient("",
+		WithAPIBaseURL("http://localhost/api/"),
+		WithImageBaseURL("http://localhost/image"),
+	)
+	s.Assert().Equal("http://localhost/api", client.apiEndpointBase)
+	s.Assert().Equal("http://localhost/image", client.imageEndpointBase)
+
+	botClient := NewBotClient("", WithAPIBaseURL("http://localhost/api"))
+	s.Assert().Equal("http://localhost/api", botClient.apiEndpointBase)
+	s.Assert().Equal(GroupMeImageBase, botClient.imageEndpointBase)
+}
+
 func TestClientSuite(t *testing.T) {
 	suite.Run(t, new(ClientSuite))
 }
@@ -0,0 +1,257 @@
+package groupme
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// UnmarshalJSON decodes the attachment, keeping a copy of the JSON in Raw
+func (a *Attachment) UnmarshalJSON(data []byte) error {
+	type attachment Attachment
+	var decoded attachment
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*a = Attachment(decoded)
+	a.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON encodes the attachment. Attachments of unknown types are
+// encoded from Raw, so their fields survive a round trip
+func (a Attachment) MarshalJSON() ([]byte, error) {
+	if _, unknown := a.Typed().(*UnknownAttachment); unknown && len(a.Raw) > 0 {
+		return a.Raw, nil
+	}
+
+	type attachment Attachment
+	return json.Marshal(attachment(a))
+}
+
+// TypedAttachment is one of the *...Attachment types returned by Attachment.Typed
+type TypedAttachment interface {
+	AttachmentType() attachmentType
+}
+
+// MentionsAttachment mentions users. Each of Loci is the [offset, length]
+// of the mention of the user at the same index of UserIDs, in UTF-16 code units
+type MentionsAttachment struct {
+	Loci    [][]int
+	UserIDs []string
+}
+
+// ImageAttachment is a picture hosted by the image service
+type ImageAttachment struct {
+	URL string
+}
+
+// LocationAttachment is a named location
+type LocationAttachment struct {
+	Name      string
+	Latitude  string
+	Longitude string
+}
+
+// EmojiAttachment maps placeholder characters in the text to emoji PowerUps.
+// Each of Charmap is a [pack_id, offset] pair
+type EmojiAttachment struct {
+	Placeholder string
+	Charmap     [][]int
+}
+
+// ReplyAttachment marks the message as a reply
+type ReplyAttachment struct {
+	ReplyID     string
+	BaseReplyID string
+	UserID      string
+}
+
+// SplitAttachment is a bill splitting request
+type SplitAttachment struct {
+	Token string
+}
+
+// FileAttachment is a file hosted by the file service
+type FileAttachment struct {
+	FileID string
+}
+
+// VideoAttachment is a video hosted by the video service
+type VideoAttachment struct {
+	URL        string
+	PreviewURL string
+}
+
+// PollAttachment is a group poll
+type PollAttachment struct {
+	PollID string
+}
+
+// EventAttachment is a group calendar event
+type EventAttachment struct {
+	EventID string
+	View    string
+}
+
+// UnknownAttachment is an attachment type without typed fields
+type UnknownAttachment struct {
+	Type attachmentType
+	Raw  json.RawMessage
+}
+
+func (*MentionsAttachment) AttachmentType() attachmentType { return Mentions }
+func (*ImageAttachment) AttachmentType() attachmentType    { return Image }
+func (*LocationAttachment) AttachmentType() attachmentType { return Location }
+func (*EmojiAttachment) AttachmentType() attachmentType    { return Emoji }
+func (*ReplyAttachment) AttachmentType() attachmentType    { return Reply }
+func (*SplitAttachment) AttachmentType() attachmentType    { return Split }
+func (*FileAttachment) AttachmentType() attachmentType     { return File }
+func (*VideoAttachment) AttachmentType() attachmentType    { return Video }
+func (*PollAttachment) AttachmentType() attachmentType     { return Poll }
+func (*EventAttachment) AttachmentType() attachmentType    { return Event }
+func (a *UnknownAttachment) AttachmentType() attachmentType {
+	return a.Type
+}
+
+// Typed returns the attachment as the *...Attachment type for its Type,
+// or an *UnknownAttachment:
+//
+//	switch attachment := a.Typed().(type) {
+//	case *groupme.ImageAttachment:
+//		...
+//	}
+func (a Attachment) Typed() TypedAttachment {
+	switch a.Type {
+	case Mentions:
+		return &MentionsAttachment{Loci: a.Loci, UserIDs: a.UserIDs}
+	case Image:
+		return &ImageAttachment{URL: a.URL}
+	case Location:
+		return &LocationAttachment{Name: a.Name, Latitude: a.Latitude, Longitude: a.Longitude}
+	case Emoji:
+		return &EmojiAttachment{Placeholder: a.Placeholder, Charmap: a.Charmap}
+	case Reply:
+		return &ReplyAttachment{ReplyID: a.ReplyID, BaseReplyID: a.BaseReplyID, UserID: a.UserID}
+	case Split:
+		return &SplitAttachment{Token: a.Token}
+	case File:
+		return &FileAttachment{FileID: a.FileID}
+	case Video:
+		return &VideoAttachment{URL: a.URL, PreviewURL: a.PreviewURL}
+	case Poll:
+		return &PollAttachment{PollID: a.PollID}
+	case Event:
+		return &EventAttachment{EventID: a.EventID, View: a.View}
+	default:
+		return &UnknownAttachment{Type: a.Type, Raw: a.Raw}
+	}
+}
+
+/*//////// Mentions ////////*/
+
+// MentionSpan is a mention of a user in a message's text
+type MentionSpan struct {
+	UserID string
+	// Offset and Length of the mention in the text, in UTF-16 code units
+	Offset int
+	Length int
+	// Text is the mention's substring of the text, e.g. "@nickname"
+	Text string
+}
+
+// MentionedUserIDs returns the IDs of the users mentioned in the message, without duplicates
+func (m *Message) MentionedUserIDs() []string {
+	var userIDs []string
+	seen := map[string]bool{}
+	for _, span := range m.MentionSpans() {
+		if !seen[span.UserID] {
+			seen[span.UserID] = true
+			userIDs = append(userIDs, span.UserID)
+		}
+	}
+	return userIDs
+}
+
+// MentionSpans resolves the loci of the message's mentions attachments to
+// substrings of its text. Loci outside the text are skipped
+func (m *Message) MentionSpans() []MentionSpan {
+	text := utf16.Encode([]rune(m.Text))
+
+	var spans []MentionSpan
+	for _, attachment := range m.Attachments {
+		if attachment == nil || attachment.Type != Mentions {
+			continue
+		}
+
+		for i, locus := range attachment.Loci {
+			if i >= len(attachment.UserIDs) || len(locus) != 2 {
+				break
+			}
+
+			offset, length := locus[0], locus[1]
+			if offset < 0 || length < 0 || offset+length > len(text) {
+				continue
+			}
+
+			spans = append(spans, MentionSpan{
+				UserID: attachment.UserIDs[i],
+				Offset: offset,
+				Length: length,
+				Text:   string(utf16.Decode(text[offset : offset+length])),
+			})
+		}
+	}
+	return spans
+}
+
+// RenderMentions returns the message text with each mention replaced by
+// mention(span) and the text between them by text(substring), such as
+// to highlight mentions or escape the text. Overlapping mentions, such
+// as the loci of @all, keep the first
+func (m *Message) RenderMentions(mention func(span MentionSpan) string, text func(s string) string) string {
+	spans := m.MentionSpans()
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Offset < spans[j].Offset
+	})
+
+	units := utf16.Encode([]rune(m.Text))
+	var b strings.Builder
+	offset := 0
+	for _, span := range spans {
+		if span.Offset < offset {
+			continue
+		}
+
+		b.WriteString(text(string(utf16.Decode(units[offset:span.Offset]))))
+		b.WriteString(mention(span))
+		offset = span.Offset + span.Length
+	}
+	b.WriteString(text(string(utf16.Decode(units[offset:]))))
+	return b.String()
+}
+
+/*//////// Pictures ////////*/
+
+// PictureURLs returns the URLs of the message's picture and image
+// attachments, without duplicates
+func (m *Message) PictureURLs() []string {
+	var urls []string
+	seen := map[string]bool{}
+	add := func(url string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+
+	add(m.ImageURL)
+	for _, attachment := range m.Attachments {
+		if attachment != nil && attachment.Type == Image {
+			add(attachment.URL)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,80 @@
+package groupme
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachment_Typed(t *testing.T) {
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"text": "Hi @Alice 🎉 and @Bob",
+		"attachments": [
+			{"type": "mentions", "loci": [[3, 6], [17, 4], [3, 6]], "user_ids": ["1", "2", "1"]},
+			{"type": "image", "url": "https://i.groupme.com/123"},
+			{"type": "reply", "reply_id": "10", "base_reply_id": "9", "user_id": "2"},
+			{"type": "video", "url": "https://v.groupme.com/1.mp4", "preview_url": "https://v.groupme.com/1.jpeg"},
+			{"type": "event", "event_id": "abc", "view": "full"},
+			{"type": "postcard", "design": "birthday"}
+		]
+	}`), &msg))
+	require.Len(t, msg.Attachments, 6)
+
+	assert.Equal(t, &MentionsAttachment{Loci: [][]int{{3, 6}, {17, 4}, {3, 6}}, UserIDs: []string{"1", "2", "1"}}, msg.Attachments[0].Typed())
+	assert.Equal(t, &ImageAttachment{URL: "https://i.groupme.com/123"}, msg.Attachments[1].Typed())
+	assert.Equal(t, &ReplyAttachment{ReplyID: "10", BaseReplyID: "9", UserID: "2"}, msg.Attachments[2].Typed())
+	assert.Equal(t, &VideoAttachment{URL: "https://v.groupme.com/1.mp4", PreviewURL: "https://v.groupme.com/1.jpeg"}, msg.Attachments[3].Typed())
+	assert.Equal(t, &EventAttachment{EventID: "abc", View: "full"}, msg.Attachments[4].Typed())
+
+	unknown, ok := msg.Attachments[5].Typed().(*UnknownAttachment)
+	require.True(t, ok)
+	assert.Equal(t, attachmentType("postcard"), unknown.AttachmentType())
+	assert.JSONEq(t, `{"type": "postcard", "design": "birthday"}`, string(unknown.Raw))
+
+	// Unknown attachments survive a round trip
+	jsonBytes, err := json.Marshal(msg.Attachments[5])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "postcard", "design": "birthday"}`, string(jsonBytes))
+
+	assert.Equal(t, []string{"1", "2"}, msg.MentionedUserIDs())
+	spans := msg.MentionSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, MentionSpan{UserID: "1", Offset: 3, Length: 6, Text: "@Alice"}, spans[0])
+	assert.Equal(t, MentionSpan{UserID: "2", Offset: 17, Length: 4, Text: "@Bob"}, spans[1])
+}
+
+func TestMessage_MentionSpans_OutOfRange(t *testing.T) {
+	msg := Message{
+		Text:        "@Al",
+		Attachments: []*Attachment{{Type: Mentions, Loci: [][]int{{0, 6}, {0, 3}}, UserIDs: []string{"1", "2"}}},
+	}
+	assert.Equal(t, []MentionSpan{{UserID: "2", Offset: 0, Length: 3, Text: "@Al"}}, msg.MentionSpans())
+}
+
+func TestMessage_RenderMentions(t *testing.T) {
+	msg := Message{
+		Text:        "😀 @Al & @all",
+		Attachments: []*Attachment{{Type: Mentions, Loci: [][]int{{9, 4}, {3, 3}, {9, 4}}, UserIDs: []string{"2", "1", "3"}}},
+	}
+	rendered := msg.RenderMentions(
+		func(span MentionSpan) string { return "<" + span.UserID + ":" + span.Text + ">" },
+		func(s string) string { return strings.ReplaceAll(s, "&", "&amp;") },
+	)
+	assert.Equal(t, "😀 <1:@Al> &amp; <2:@all>", rendered)
+}
+
+func TestMessage_PictureURLs(t *testing.T) {
+	msg := Message{
+		ImageURL: "https://i.groupme.com/1",
+		Attachments: []*Attachment{
+			{Type: Image, URL: "https://i.groupme.com/1"},
+			{Type: Location, URL: "https://example.com"},
+			{Type: Image, URL: "https://i.groupme.com/2"},
+		},
+	}
+	assert.Equal(t, []string{"https://i.groupme.com/1", "https://i.groupme.com/2"}, msg.PictureURLs())
+}
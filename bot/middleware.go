@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrForbidden is returned by AllowUsers for messages from other users
+	ErrForbidden = errors.New("bot: user not allowed")
+	// ErrCooldown is returned by Cooldown for messages sent during the user's cooldown
+	ErrCooldown = errors.New("bot: user is cooling down")
+)
+
+// now is overridden in tests
+var now = time.Now
+
+// Logging logs each handled message and its error, if any
+func Logging(logger *log.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			start := now()
+			err := next(c)
+			logger.Printf("bot: %s (%s) %q handled in %s, error: %v",
+				c.Message.Name, c.Message.UserID, c.Message.Text, now().Sub(start), err)
+			return err
+		}
+	}
+}
+
+// AllowUsers only runs the handler for messages from the users, returning ErrForbidden otherwise
+func AllowUsers(userIDs ...string) Middleware {
+	allowed := map[string]bool{}
+	for _, userID := range userIDs {
+		allowed[userID] = true
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if !allowed[c.Message.UserID] {
+				return ErrForbidden
+			}
+			return next(c)
+		}
+	}
+}
+
+// Cooldown runs the handler at most once per period for each user,
+// returning ErrCooldown otherwise. Each Cooldown tracks its users
+// separately, so it can limit a single route or the whole Router
+func Cooldown(period time.Duration) Middleware {
+	var (
+		mu   sync.Mutex
+		last = map[string]time.Time{}
+	)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			mu.Lock()
+			t := now()
+			if previous, ok := last[c.Message.UserID]; ok && t.Sub(previous) < period {
+				mu.Unlock()
+				return ErrCooldown
+			}
+			last[c.Message.UserID] = t
+			mu.Unlock()
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Package bot routes GroupMe bot callback messages to command handlers.
+//
+// A Router matches each message sent to the bot's callback URL by command
+// prefix, regular expression, or mention of the bot, and replies through
+// a groupme.BotClient:
+//
+//	router := bot.NewRouter(groupme.NewBotClient(botID), bot.WithName("hal"))
+//	router.Use(bot.Logging(log.Default()))
+//	router.Command("!ping", func(c *bot.Context) error {
+//		return c.Reply("pong")
+//	})
+//	http.Handle("/callback", router.HTTPHandlerFunc())
+package bot
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/densestvoid/groupme"
+)
+
+// Context is a message matched by a route
+type Context struct {
+	context.Context
+	Message groupme.Message
+	// Command is the matched command prefix, if matched by Command
+	Command string
+	// Args are the arguments following the command or mention
+	Args []string
+	// Matches are the submatches, if matched by Regexp
+	Matches []string
+
+	router *Router
+}
+
+// Reply posts text to the group as the bot
+func (c *Context) Reply(text string, options ...groupme.BotMessageOption) error {
+	return c.router.client.PostBotMessageWithOptions(c, text, options...)
+}
+
+// ReplyTo posts text to the group as the bot, as a reply to the matched message
+// in the thread of replies it belongs to
+func (c *Context) ReplyTo(text string, options ...groupme.BotMessageOption) error {
+	baseReplyID := c.Message.ID
+	for _, attachment := range c.Message.Attachments {
+		if attachment != nil && attachment.Type == groupme.Reply && attachment.BaseReplyID != "" {
+			baseReplyID = attachment.BaseReplyID
+		}
+	}
+	return c.Reply(text, append([]groupme.BotMessageOption{groupme.WithBotReply(c.Message.ID, baseReplyID)}, options...)...)
+}
+
+// HandlerFunc handles a matched message
+type HandlerFunc func(*Context) error
+
+// Middleware wraps a HandlerFunc, running before and/or after it
+type Middleware func(HandlerFunc) HandlerFunc
+
+type route struct {
+	match   func(c *Context) bool
+	handler HandlerFunc
+}
+
+// Router dispatches bot callback messages to the first matching route
+type Router struct {
+	client       *groupme.BotClient
+	name         string
+	errorHandler func(*Context, error)
+
+	middleware []Middleware
+	routes     []route
+	fallback   HandlerFunc
+}
+
+// RouterOption configures a Router
+type RouterOption func(*Router)
+
+// WithName sets the bot's name, which Mention routes match as "@name"
+func WithName(name string) RouterOption {
+	return func(r *Router) {
+		r.name = name
+	}
+}
+
+// WithErrorHandler sets the function called with handler errors from
+// Callback and HTTPHandlerFunc. Defaults to logging the error
+func WithErrorHandler(handler func(*Context, error)) RouterOption {
+	return func(r *Router) {
+		r.errorHandler = handler
+	}
+}
+
+// NewRouter creates a Router replying through the client
+func NewRouter(client *groupme.BotClient, options ...RouterOption) *Router {
+	r := &Router{
+		client: client,
+		errorHandler: func(c *Context, err error) {
+			log.Printf("bot: handling message %s: %v", c.Message.ID, err)
+		},
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Use adds middleware run for every route, in the order added
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Command routes messages starting with the prefix, such as "!ping".
+// The rest of the text is split into Args
+func (r *Router) Command(prefix string, handler HandlerFunc, middleware ...Middleware) {
+	r.add(func(c *Context) bool {
+		text := strings.TrimSpace(c.Message.Text)
+		command := firstField(text)
+		if !strings.EqualFold(command, prefix) {
+			return false
+		}
+
+		c.Command = prefix
+		c.Args = ParseArgs(text[len(command):])
+		return true
+	}, handler, middleware)
+}
+
+// Regexp routes messages matching the regular expression, setting Matches
+func (r *Router) Regexp(re *regexp.Regexp, handler HandlerFunc, middleware ...Middleware) {
+	r.add(func(c *Context) bool {
+		c.Matches = re.FindStringSubmatch(c.Message.Text)
+		return c.Matches != nil
+	}, handler, middleware)
+}
+
+// Mention routes messages mentioning the bot by name, as set with WithName.
+// The text around the mention is split into Args
+func (r *Router) Mention(handler HandlerFunc, middleware ...Middleware) {
+	if r.name == "" {
+		r.add(func(*Context) bool { return false }, handler, middleware)
+		return
+	}
+
+	// The name must not be followed by a letter, digit or underscore,
+	// so "@halbert" doesn't mention "hal"
+	mention := regexp.MustCompile(`(?i)@` + regexp.QuoteMeta(r.name) + `($|[^\pL\pN_])`)
+	r.add(func(c *Context) bool {
+		match := mention.FindStringSubmatchIndex(c.Message.Text)
+		if match == nil {
+			return false
+		}
+
+		text := c.Message.Text
+		c.Args = ParseArgs(text[:match[0]] + " " + text[match[2]:])
+		return true
+	}, handler, middleware)
+}
+
+// Default handles messages matching no other route
+func (r *Router) Default(handler HandlerFunc, middleware ...Middleware) {
+	r.fallback = chain(handler, middleware)
+}
+
+func (r *Router) add(match func(c *Context) bool, handler HandlerFunc, middleware []Middleware) {
+	r.routes = append(r.routes, route{match: match, handler: chain(handler, middleware)})
+}
+
+// Handle dispatches the message to the first matching route. Messages
+// sent by bots, including this one, are ignored to avoid reply loops
+func (r *Router) Handle(ctx context.Context, msg groupme.Message) error {
+	return r.handle(&Context{Context: ctx, Message: msg, router: r})
+}
+
+func (r *Router) handle(c *Context) error {
+	if c.Message.SenderType == groupme.SenderTypeBot {
+		return nil
+	}
+
+	handler := r.fallback
+	for _, route := range r.routes {
+		if route.match(c) {
+			handler = route.handler
+			break
+		}
+		c.Command, c.Args, c.Matches = "", nil, nil
+	}
+	if handler == nil {
+		return nil
+	}
+
+	return chain(handler, r.middleware)(c)
+}
+
+// Callback returns an HTTPMessageCallback handling each message,
+// passing errors to the Router's error handler
+func (r *Router) Callback() groupme.HTTPMessageCallback {
+	return func(msg groupme.Message) {
+		c := &Context{Context: context.Background(), Message: msg, router: r}
+		if err := r.handle(c); err != nil {
+			r.errorHandler(c, err)
+		}
+	}
+}
+
+// HTTPHandlerFunc returns a handler for the bot's callback URL
+func (r *Router) HTTPHandlerFunc() http.HandlerFunc {
+	return groupme.HTTPHandlerFunc(r.Callback())
+}
+
+// chain wraps the handler in the middleware, so the first middleware runs first
+func chain(handler HandlerFunc, middleware []Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+func firstField(text string) string {
+	if fields := strings.Fields(text); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// ParseArgs splits text into whitespace separated arguments.
+// Double quotes group words into one argument
+func ParseArgs(text string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quoted  bool
+		inArg   bool
+	)
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/densestvoid/groupme/groupmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRouter returns a Router for a bot in a group on a groupmetest.Server
+func newTestRouter(t *testing.T, options ...RouterOption) (*Router, *groupmetest.Server, string) {
+	server := groupmetest.NewServer()
+	t.Cleanup(server.Close)
+
+	token := server.AddUser(groupme.User{Name: "Owner"})
+	client := groupme.NewClient(token, server.ClientOptions()...)
+	owner, err := client.MyUser(context.Background())
+	require.NoError(t, err)
+
+	group := server.AddGroup(owner.ID, groupme.GroupSettings{Name: "Bots"})
+	b, err := client.CreateBot(context.Background(), &groupme.Bot{Name: "hal", GroupID: group.ID})
+	require.NoError(t, err)
+
+	return NewRouter(groupme.NewBotClient(b.BotID, server.ClientOptions()...), options...), server, group.ID
+}
+
+func replies(server *groupmetest.Server, groupID string) []string {
+	var texts []string
+	for _, msg := range server.Messages(groupID) {
+		texts = append(texts, msg.Text)
+	}
+	return texts
+}
+
+func TestRouter(t *testing.T) {
+	router, server, groupID := newTestRouter(t, WithName("Hal"))
+	ctx := context.Background()
+
+	router.Command("!echo", func(c *Context) error {
+		return c.Reply(strings.Join(c.Args, "|"))
+	})
+	router.Regexp(regexp.MustCompile(`^roll (\d+)$`), func(c *Context) error {
+		return c.Reply("rolling " + c.Matches[1])
+	})
+	router.Mention(func(c *Context) error {
+		return c.Reply("you said " + strings.Join(c.Args, " "))
+	})
+	router.Default(func(c *Context) error {
+		return c.Reply("unknown")
+	})
+
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: `!ECHO a "b c" d`}))
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "roll 20"}))
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "hey @hal open the doors"}))
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "!echoes"}))
+
+	// Bot messages are ignored
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "!echo loop", SenderType: groupme.SenderTypeBot}))
+
+	assert.Equal(t, []string{"a|b c|d", "rolling 20", "you said hey open the doors", "unknown"}, replies(server, groupID))
+}
+
+func TestRouter_Mention(t *testing.T) {
+	router, server, groupID := newTestRouter(t, WithName("Hal"))
+	router.Mention(func(c *Context) error {
+		return c.Reply("you said " + strings.Join(c.Args, " "))
+	})
+	router.Default(func(c *Context) error {
+		return c.Reply("unknown")
+	})
+	ctx := context.Background()
+
+	// Lowercasing Ⱥ changes its length in bytes
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "Ⱥ@HAL, hi"}))
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "ask @halbert"}))
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "@hal"}))
+
+	assert.Equal(t, []string{"you said Ⱥ , hi", "unknown", "you said "}, replies(server, groupID))
+}
+
+func TestContext_ReplyTo(t *testing.T) {
+	router, server, groupID := newTestRouter(t)
+	router.Default(func(c *Context) error {
+		return c.ReplyTo("pong", groupme.WithBotPicture("https://i.groupme.com/1"))
+	})
+
+	ctx := context.Background()
+	require.NoError(t, router.Handle(ctx, groupme.Message{ID: "42", Text: "ping"}))
+	// A reply continues the thread of the message replied to
+	require.NoError(t, router.Handle(ctx, groupme.Message{ID: "43", Text: "ping", Attachments: []*groupme.Attachment{
+		{Type: groupme.Reply, ReplyID: "42", BaseReplyID: "40"},
+	}}))
+
+	messages := server.Messages(groupID)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "pong", messages[0].Text)
+	assert.Equal(t, "https://i.groupme.com/1", messages[0].ImageURL)
+	require.Len(t, messages[0].Attachments, 1)
+	assert.Equal(t, groupme.Reply, messages[0].Attachments[0].Type)
+	assert.Equal(t, "42", messages[0].Attachments[0].ReplyID)
+	assert.Equal(t, "42", messages[0].Attachments[0].BaseReplyID)
+	require.Len(t, messages[1].Attachments, 1)
+	assert.Equal(t, "43", messages[1].Attachments[0].ReplyID)
+	assert.Equal(t, "40", messages[1].Attachments[0].BaseReplyID)
+}
+
+func TestRouter_Middleware(t *testing.T) {
+	router, server, groupID := newTestRouter(t)
+	ctx := context.Background()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) error {
+				order = append(order, name)
+				return next(c)
+			}
+		}
+	}
+	router.Use(trace("first"), trace("second"))
+	router.Command("!admin", func(c *Context) error {
+		return c.Reply("ok")
+	}, trace("route"), AllowUsers("1"))
+
+	require.NoError(t, router.Handle(ctx, groupme.Message{Text: "!admin", UserID: "1"}))
+	assert.Equal(t, []string{"first", "second", "route"}, order)
+
+	err := router.Handle(ctx, groupme.Message{Text: "!admin", UserID: "2"})
+	assert.True(t, errors.Is(err, ErrForbidden))
+	assert.Equal(t, []string{"ok"}, replies(server, groupID))
+}
+
+func TestCooldown(t *testing.T) {
+	current := time.Unix(0, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	calls := 0
+	handler := Cooldown(time.Minute)(func(c *Context) error {
+		calls++
+		return nil
+	})
+
+	assert.NoError(t, handler(&Context{Message: groupme.Message{UserID: "1"}}))
+	assert.True(t, errors.Is(handler(&Context{Message: groupme.Message{UserID: "1"}}), ErrCooldown))
+	assert.NoError(t, handler(&Context{Message: groupme.Message{UserID: "2"}}))
+
+	current = current.Add(time.Minute)
+	assert.NoError(t, handler(&Context{Message: groupme.Message{UserID: "1"}}))
+	assert.Equal(t, 3, calls)
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	handler := Logging(log.New(&buf, "", 0))(func(c *Context) error {
+		return errors.New("failed")
+	})
+
+	assert.Error(t, handler(&Context{Message: groupme.Message{Name: "Alice", Text: "!ping"}}))
+	assert.Contains(t, buf.String(), `Alice`)
+	assert.Contains(t, buf.String(), `"!ping"`)
+	assert.Contains(t, buf.String(), `failed`)
+}
+
+func TestRouter_Callback(t *testing.T) {
+	errs := make(chan error, 1)
+	router, _, _ := newTestRouter(t, WithErrorHandler(func(c *Context, err error) {
+		errs <- err
+	}))
+	router.Default(func(c *Context) error {
+		return errors.New("failed")
+	})
+
+	router.Callback()(groupme.Message{Text: "hello"})
+	assert.EqualError(t, <-errs, "failed")
+}
+
+func TestParseArgs(t *testing.T) {
+	assert.Equal(t, []string{"a", "b c", ""}, ParseArgs(` a  "b c" ""`))
+	assert.Nil(t, ParseArgs("   "))
+}
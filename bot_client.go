@@ -8,7 +8,9 @@ import (
 	"net/http"
 )
 
-// Client posts bot messages
+// BotClient posts bot messages. With WithImageToken, it also uploads
+// pictures with UploadPictureReader, UploadPictureBytes, UploadPictureFile
+// and UploadPictureURL
 type BotClient struct {
 	client
 	botID string
@@ -16,34 +18,87 @@ type BotClient struct {
 
 // NewBotClient creates a new GroupMe API Client to post bot messages
 func NewBotClient(botID string, options ...ClientOption) *BotClient {
-	client := &BotClient{
-		client: client{
-			httpClient:        &http.Client{},
-			apiEndpointBase:   GroupMeAPIBase,
-			imageEndpointBase: GroupMeImageBase,
-		},
-		botID: botID,
+	return &BotClient{
+		client: newClient(options),
+		botID:  botID,
 	}
+}
 
-	for _, option := range options {
-		option(&client.client)
+// PostBotMessage - Post a message from a bot
+func (c *BotClient) PostBotMessage(ctx context.Context, text string, pictureURL *string) error {
+	var options []BotMessageOption
+	if pictureURL != nil {
+		options = append(options, WithBotPicture(*pictureURL))
 	}
+	return c.PostBotMessageWithOptions(ctx, text, options...)
+}
 
-	return client
+// botMessage is the body of a bot post
+type botMessage struct {
+	BotID       string        `json:"bot_id"`
+	Text        string        `json:"text"`
+	PictureURL  string        `json:"picture_url,omitempty"`
+	Attachments []*Attachment `json:"attachments,omitempty"`
 }
 
-// PostBotMessage - Post a message from a bot
-func (c *BotClient) PostBotMessage(ctx context.Context, text string, pictureURL *string) error {
+// BotMessageOption adds to a bot message
+type BotMessageOption func(*botMessage)
+
+// WithBotPicture attaches a picture hosted by the image service
+func WithBotPicture(pictureURL string) BotMessageOption {
+	return func(m *botMessage) {
+		m.PictureURL = pictureURL
+	}
+}
+
+// WithBotAttachments adds attachments, such as those of a message built
+// by a MessageBuilder
+func WithBotAttachments(attachments ...*Attachment) BotMessageOption {
+	return func(m *botMessage) {
+		m.Attachments = append(m.Attachments, attachments...)
+	}
+}
+
+// WithBotMentions mentions the users. Each of loci is the [offset, length]
+// of the mention of the user at the same index, in UTF-16 code units
+func WithBotMentions(userIDs []string, loci [][]int) BotMessageOption {
+	return WithBotAttachments(&Attachment{Type: Mentions, UserIDs: userIDs, Loci: loci})
+}
+
+// WithBotLocation attaches a named location
+func WithBotLocation(name, latitude, longitude string) BotMessageOption {
+	return WithBotAttachments(&Attachment{Type: Location, Name: name, Latitude: latitude, Longitude: longitude})
+}
+
+// WithBotReply marks the message as a reply to the message replyID. baseReplyID
+// is the first message of the thread of replies, which is replyID itself unless
+// it is a reply. An empty baseReplyID is left unset
+func WithBotReply(replyID, baseReplyID string) BotMessageOption {
+	return WithBotAttachments(&Attachment{Type: Reply, ReplyID: replyID, BaseReplyID: baseReplyID})
+}
+
+/*
+PostBotMessageWithOptions -
+
+Post a message from a bot, with a picture or attachments
+added by the options:
+
+	err := client.PostBotMessageWithOptions(ctx, "@Bob see above",
+		groupme.WithBotMentions([]string{bobUserID}, [][]int{{0, 4}}),
+		groupme.WithBotReply(messageID, messageID),
+	)
+*/
+func (c *BotClient) PostBotMessageWithOptions(ctx context.Context, text string, options ...BotMessageOption) error {
 	URL := fmt.Sprintf(c.apiEndpointBase + postBotMessageEndpoint)
 
-	var data = struct {
-		BotID      string  `json:"bot_id"`
-		Text       string  `json:"text"`
-		PictureURL *string `json:",omitempty"`
-	}{
-		c.botID,
-		text,
-		pictureURL,
+	data := botMessage{BotID: c.botID, Text: text}
+	for _, option := range options {
+		option(&data)
+	}
+
+	m := &Message{Text: text, ImageURL: data.PictureURL, Attachments: data.Attachments}
+	if err := c.validate(m.validate); err != nil {
+		return err
 	}
 
 	jsonBytes, err := json.Marshal(&data)
@@ -56,5 +111,6 @@ func (c *BotClient) PostBotMessage(ctx context.Context, text string, pictureURL
 		return err
 	}
 
+	ctx = withRateLimitScope(ctx, RequestClassMessage, "bot:"+c.botID)
 	return c.do(ctx, httpReq, nil)
 }
@@ -27,6 +27,10 @@ const (
 // CreateBot - Create a bot. See the Bots Tutorial (https://dev.groupme.com/tutorials/bots)
 // for a full walkthrough.
 func (c *Client) CreateBot(ctx context.Context, bot *Bot) (*Bot, error) {
+	if err := c.validate(bot.validate); err != nil {
+		return nil, err
+	}
+
 	URL := c.apiEndpointBase + createBotEndpoint
 
 	var data = struct {
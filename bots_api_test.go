@@ -2,6 +2,7 @@ package groupme
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -39,6 +40,22 @@ func (s *BotsAPISuite) TestBotsPostMessage() {
 	s.Require().NoError(err)
 }
 
+func (s *BotsAPISuite) TestBotsPostMessagePicture() {
+	pictureURL := "https://i.groupme.com/123456789"
+	err := s.botClient.PostBotMessage(context.Background(), "test message", &pictureURL)
+	s.Require().NoError(err)
+}
+
+func (s *BotsAPISuite) TestBotsPostMessageWithOptions() {
+	err := s.botClient.PostBotMessageWithOptions(context.Background(), "@test message",
+		WithBotPicture("https://i.groupme.com/123456789"),
+		WithBotMentions([]string{"1"}, [][]int{{0, 5}}),
+		WithBotLocation("Home", "40.7", "-74.0"),
+		WithBotReply("2", "1"),
+	)
+	s.Require().NoError(err)
+}
+
 func (s *BotsAPISuite) TestBotsIndex() {
 	bots, err := s.client.IndexBots(context.Background())
 	s.Require().NoError(err)
@@ -87,6 +104,19 @@ func botsTestRouter() *mux.Router {
 		Methods("POST").
 		Name("PostBotMessage").
 		HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// Only the fields GroupMe expects are accepted
+			var data struct {
+				BotID       string            `json:"bot_id"`
+				Text        string            `json:"text"`
+				PictureURL  string            `json:"picture_url"`
+				Attachments []json.RawMessage `json:"attachments"`
+			}
+			decoder := json.NewDecoder(req.Body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&data); err != nil || data.BotID == "" {
+				w.WriteHeader(400)
+				return
+			}
 			w.WriteHeader(201)
 		})
 
@@ -6,11 +6,15 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 )
 
 // GroupMeAPIBase - Endpoints are added on to this to get the full URI.
-// Overridable for testing
+// Overridable with WithAPIBaseURL
 const GroupMeAPIBase = "https://api.groupme.com/v3"
+
+// GroupMeImageBase - Image service endpoints are added on to this.
+// Overridable with WithImageBaseURL
 const GroupMeImageBase = "https://image.groupme.com"
 
 // client communicates with the GroupMe API to perform actions
@@ -19,6 +23,11 @@ type client struct {
 	httpClient        *http.Client
 	apiEndpointBase   string
 	imageEndpointBase string
+	pushEndpointBase  string
+	retryPolicy       *RetryPolicy
+	rateLimiter       *RateLimiter
+	validation        bool
+	imageToken        string
 }
 
 type ClientOption func(client *client)
@@ -29,6 +38,46 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithAPIBaseURL sends API requests to baseURL instead of GroupMeAPIBase,
+// e.g. a local stand-in, a recording proxy or an egress gateway
+func WithAPIBaseURL(baseURL string) ClientOption {
+	return func(client *client) {
+		client.apiEndpointBase = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithImageBaseURL sends image service requests to baseURL instead of GroupMeImageBase
+func WithImageBaseURL(baseURL string) ClientOption {
+	return func(client *client) {
+		client.imageEndpointBase = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithImageToken sets the access token authorizing picture uploads to the
+// image service. Required for a BotClient to upload pictures; a Client
+// uses its own token by default
+func WithImageToken(token string) ClientOption {
+	return func(client *client) {
+		client.imageToken = token
+	}
+}
+
+// newClient returns a client for the GroupMe services, with the options applied
+func newClient(options []ClientOption) client {
+	c := client{
+		httpClient:        &http.Client{},
+		apiEndpointBase:   GroupMeAPIBase,
+		imageEndpointBase: GroupMeImageBase,
+		pushEndpointBase:  GroupMePushBase,
+	}
+
+	for _, option := range options {
+		option(&c)
+	}
+
+	return c
+}
+
 // Close safely shuts down the Client
 func (c *client) Close() error {
 	c.httpClient.CloseIdleConnections()
@@ -53,17 +102,43 @@ func (r response) UnmarshalJSON(bs []byte) error {
 	return json.NewDecoder(bytes.NewBuffer(bs)).Decode(r.i)
 }
 
+// unwrappedResponse parses a response body that isn't wrapped in a
+// response and meta, such as those of the image service
+type unwrappedResponse struct {
+	i interface{}
+}
+
 const errorStatusCodeMin = 300
 
 func (c *client) do(ctx context.Context, req *http.Request, i interface{}) error {
 	req = req.WithContext(ctx)
-	if req.Method == "POST" {
+	if req.Method == "POST" && req.Header.Get("Content-Type") == "" {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	send := func(req *http.Request) (*http.Response, error) {
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(req.Context(), req); err != nil {
+				return nil, err
+			}
+		}
+		return c.doOnce(req, i)
+	}
+
+	if c.retryPolicy == nil {
+		_, err := send(req)
+		return err
+	}
+
+	return c.retryPolicy.run(ctx, req, send)
+}
+
+// doOnce sends the request a single time and parses the response into i.
+// The returned response, if not nil, has had its body consumed
+func (c *client) doOnce(req *http.Request, i interface{}) (*http.Response, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -73,34 +148,34 @@ func (c *client) do(ctx context.Context, req *http.Request, i interface{}) error
 		readBytes, err = io.ReadAll(resp.Body)
 		if err != nil {
 			// We couldn't read the output.  Oh well; generate the appropriate error type anyway.
-			return &Meta{
-				Code: resp.StatusCode,
-			}
+			return resp, newAPIError(req, resp, nil, Meta{})
 		}
 
 		jsonResp := newJSONResponse(nil)
 		if err = json.Unmarshal(readBytes, &jsonResp); err != nil {
 			// We couldn't parse the output.  Oh well; generate the appropriate error type anyway.
-			return &Meta{
-				Code: resp.StatusCode,
-			}
+			return resp, newAPIError(req, resp, readBytes, Meta{})
 		}
-		return &jsonResp.Meta
+		return resp, newAPIError(req, resp, readBytes, jsonResp.Meta)
 	}
 
 	if i == nil {
-		return nil
+		return resp, nil
 	}
 
 	readBytes, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return resp, err
+	}
+
+	if unwrapped, ok := i.(unwrappedResponse); ok {
+		return resp, json.Unmarshal(readBytes, unwrapped.i)
 	}
 
 	jsonResp := newJSONResponse(i)
 	if err := json.Unmarshal(readBytes, &jsonResp); err != nil {
-		return err
+		return resp, err
 	}
 
-	return nil
+	return resp, nil
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+
+	"github.com/densestvoid/groupme"
+)
+
+var blockColumns = []column{
+	{"USER ID", "user_id"},
+	{"BLOCKED USER ID", "blocked_user_id"},
+	{"CREATED", "created_at"},
+}
+
+func init() {
+	commands["blocks"] = &command{
+		summary: "List, check, create and remove your blocks",
+		subcommands: map[string]*command{
+			"list": {
+				summary: "List the users you blocked",
+				run:     runBlocksList,
+			},
+			"between": {
+				usage:   "<other_user_id>",
+				summary: "Check whether you and another user have blocked each other",
+				run:     runBlocksBetween,
+			},
+			"create": {
+				usage:   "<other_user_id>",
+				summary: "Block a user",
+				run:     runBlocksCreate,
+			},
+			"unblock": {
+				usage:   "<other_user_id>",
+				summary: "Unblock a user",
+				run:     runBlocksUnblock,
+			},
+		},
+	}
+}
+
+// withMyUserID runs f with a Client and the ID of its user
+func (e *env) withMyUserID(ctx context.Context, f func(client *groupme.Client, userID string) error) error {
+	return e.withClient(func(client *groupme.Client) error {
+		me, err := client.MyUser(ctx)
+		if err != nil {
+			return err
+		}
+		return f(client, me.ID)
+	})
+}
+
+func runBlocksList(ctx context.Context, e *env, args []string) error {
+	if _, err := parse(e.flags("blocks list"), args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withMyUserID(ctx, func(client *groupme.Client, userID string) error {
+		blocks, err := client.IndexBlock(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return e.print(blocks, blockColumns...)
+	})
+}
+
+func runBlocksBetween(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("blocks between"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withMyUserID(ctx, func(client *groupme.Client, userID string) error {
+		between, err := client.BlockBetween(ctx, userID, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(map[string]bool{"between": between}, column{"BETWEEN", "between"})
+	})
+}
+
+func runBlocksCreate(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("blocks create"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withMyUserID(ctx, func(client *groupme.Client, userID string) error {
+		block, err := client.CreateBlock(ctx, userID, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(block, blockColumns...)
+	})
+}
+
+func runBlocksUnblock(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("blocks unblock"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withMyUserID(ctx, func(client *groupme.Client, userID string) error {
+		return client.Unblock(ctx, userID, args[0])
+	})
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+
+	"github.com/densestvoid/groupme"
+)
+
+var botColumns = []column{
+	{"ID", "bot_id"},
+	{"GROUP ID", "group_id"},
+	{"NAME", "name"},
+	{"CALLBACK URL", "callback_url"},
+}
+
+func init() {
+	commands["bots"] = &command{
+		summary: "List, create and destroy bots, and post as a bot",
+		subcommands: map[string]*command{
+			"list": {
+				summary: "List the bots you created",
+				run:     runBotsList,
+			},
+			"create": {
+				usage:   "[-avatar-url url] [-callback-url url] [-dm-notification] <group_id> <name>",
+				summary: "Create a bot in a group",
+				run:     runBotsCreate,
+			},
+			"destroy": {
+				usage:   "<bot_id>",
+				summary: "Destroy a bot",
+				run:     runBotsDestroy,
+			},
+			"post": {
+				usage:   "[-picture-url url] <bot_id> <text>",
+				summary: "Post a message as a bot. No access token is needed",
+				run:     runBotsPost,
+			},
+		},
+	}
+}
+
+func runBotsList(ctx context.Context, e *env, args []string) error {
+	if _, err := parse(e.flags("bots list"), args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		bots, err := client.IndexBots(ctx)
+		if err != nil {
+			return err
+		}
+		return e.print(bots, botColumns...)
+	})
+}
+
+func runBotsCreate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("bots create")
+	var bot groupme.Bot
+	fs.StringVar(&bot.AvatarURL, "avatar-url", "", "image service URL of the bot's avatar")
+	fs.StringVar(&bot.CallbackURL, "callback-url", "", "URL receiving the group's messages")
+	fs.BoolVar(&bot.DMNotification, "dm-notification", false, "notify the bot of direct messages")
+	args, err := parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	bot.GroupID, bot.Name = args[0], args[1]
+
+	return e.withClient(func(client *groupme.Client) error {
+		created, err := client.CreateBot(ctx, &bot)
+		if err != nil {
+			return err
+		}
+		return e.print(created, botColumns...)
+	})
+}
+
+func runBotsDestroy(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("bots destroy"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		return client.DestroyBot(ctx, args[0])
+	})
+}
+
+func runBotsPost(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("bots post")
+	pictureURL := fs.String("picture-url", "", "image service URL of a picture to attach")
+	args, err := parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	var options []groupme.BotMessageOption
+	if *pictureURL != "" {
+		options = append(options, groupme.WithBotPicture(*pictureURL))
+	}
+	return groupme.NewBotClient(args[0], e.clientOptions...).PostBotMessageWithOptions(ctx, args[1], options...)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"github.com/densestvoid/groupme"
+)
+
+func init() {
+	commands["chats"] = &command{
+		usage:   "[-page n] [-per-page n]",
+		summary: "List your direct message chats, most recently updated first",
+		run:     runChats,
+	}
+}
+
+func runChats(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("chats")
+	var query groupme.IndexChatsQuery
+	fs.IntVar(&query.Page, "page", 1, "page of chats")
+	fs.IntVar(&query.PerPage, "per-page", 10, "chats per page")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		chats, err := client.IndexChats(ctx, &query)
+		if err != nil {
+			return err
+		}
+		return e.print(chats,
+			column{"USER ID", "other_user.id"},
+			column{"NAME", "other_user.name"},
+			column{"MESSAGES", "messages_count"},
+			column{"UPDATED", "updated_at"},
+			column{"LAST MESSAGE", "last_message.text"},
+		)
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/densestvoid/groupme"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables
+const (
+	// tokenEnv holds the access token, overriding the config file
+	tokenEnv = "GROUPME_TOKEN"
+	// configEnv holds the config file path, overridden by -config
+	configEnv = "GROUPME_CONFIG"
+)
+
+// config is the YAML config file
+type config struct {
+	Token  string `yaml:"token"`
+	Output string `yaml:"output"`
+}
+
+// loadConfig reads the config file. A missing default config file is ignored
+func (e *env) loadConfig() error {
+	e.config = &config{}
+
+	path := e.configPath
+	if path == "" {
+		path = e.getenv(configEnv)
+	}
+	explicit := path != ""
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(dir, "groupme", "config.yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, e.config); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	if e.output == "" {
+		e.output = e.config.Output
+	}
+	return nil
+}
+
+// token returns the access token from the environment or config file
+func (e *env) token() (string, error) {
+	if token := e.getenv(tokenEnv); token != "" {
+		return token, nil
+	}
+	if e.config != nil && e.config.Token != "" {
+		return e.config.Token, nil
+	}
+	return "", fmt.Errorf("no access token: set %s or the token in the config file", tokenEnv)
+}
+
+// client creates a Client authenticated with the access token
+func (e *env) client() (*groupme.Client, error) {
+	token, err := e.token()
+	if err != nil {
+		return nil, err
+	}
+	return groupme.NewClient(token, e.clientOptions...), nil
+}
+
+// withClient runs f with a Client, closing it afterwards
+func (e *env) withClient(f func(client *groupme.Client) error) error {
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return f(client)
+}
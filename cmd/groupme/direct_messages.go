@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/densestvoid/groupme"
+)
+
+func init() {
+	commands["dm"] = &command{
+		summary: "List and send direct messages",
+		subcommands: map[string]*command{
+			"list": {
+				usage:   "[-before id] [-since id] <other_user_id>",
+				summary: "List 20 direct messages with a user, newest first",
+				run:     runDMList,
+			},
+			"send": {
+				usage:   "[-image-url url] <other_user_id> <text>",
+				summary: "Send a direct message to a user",
+				run:     runDMSend,
+			},
+		},
+	}
+}
+
+func runDMList(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("dm list")
+	var query groupme.IndexDirectMessagesQuery
+	fs.StringVar(&query.BeforeID, "before", "", "list messages before this message ID")
+	fs.StringVar(&query.SinceID, "since", "", "list messages after this message ID")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		resp, err := client.IndexDirectMessages(ctx, args[0], &query)
+		if errors.Is(err, groupme.ErrNotModified) {
+			return e.print([]*groupme.Message{}, messageColumns...)
+		}
+		if err != nil {
+			return err
+		}
+		return e.print(resp.Messages, messageColumns...)
+	})
+}
+
+func runDMSend(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("dm send")
+	imageURL := fs.String("image-url", "", "image service URL of a picture to attach")
+	args, err := parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		msg, err := client.CreateDirectMessage(ctx, &groupme.Message{
+			RecipientID: args[0],
+			Text:        args[1],
+			Attachments: imageAttachments(*imageURL),
+		})
+		if err != nil {
+			return err
+		}
+		return e.print(msg, messageColumns...)
+	})
+}
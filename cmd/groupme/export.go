@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/densestvoid/groupme/export"
+)
+
+func init() {
+	commands["export"] = &command{
+		usage:   "[-dir dir] [-formats jsonl,html,text] [-images] <group_id>",
+		summary: "Export a group's history, appending only new messages to an earlier export",
+		run:     runExport,
+	}
+}
+
+func runExport(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("export")
+	dir := fs.String("dir", "", "output directory (default: the group ID)")
+	formats := fs.String("formats", "jsonl,html,text", "comma separated formats to write; jsonl is always written")
+	images := fs.Bool("images", false, "download pictures from image attachments")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	groupID := args[0]
+	if *dir == "" {
+		*dir = groupID
+	}
+
+	var parsed []export.Format
+	for _, name := range strings.Split(*formats, ",") {
+		format, err := export.ParseFormat(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, format)
+	}
+	options := []export.Option{export.WithFormats(parsed...)}
+	if *images {
+		options = append(options, export.WithImages(nil))
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result, err := export.New(client, options...).Export(ctx, groupID, *dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "Exported %d new messages of %s to %s (%d total)\n", result.New, result.Group.Name, *dir, result.Total)
+	return nil
+}
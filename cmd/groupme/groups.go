@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/densestvoid/groupme"
+)
+
+var groupColumns = []column{
+	{"ID", "id"},
+	{"NAME", "name"},
+	{"TYPE", "type"},
+	{"MEMBERS", "members"},
+	{"MESSAGES", "messages.count"},
+	{"SHARE URL", "share_url"},
+}
+
+func init() {
+	commands["groups"] = &command{
+		summary: "List, show, create, update, destroy, join and rejoin groups",
+		subcommands: map[string]*command{
+			"list": {
+				usage:   "[-page n] [-per-page n] [-omit-members]",
+				summary: "List the groups you are in",
+				run:     runGroupsList,
+			},
+			"former": {
+				summary: "List the groups you left but can rejoin",
+				run:     runGroupsFormer,
+			},
+			"show": {
+				usage:   "<group_id>",
+				summary: "Show a group",
+				run:     runGroupsShow,
+			},
+			"create": {
+				usage:   "[-description text] [-image-url url] [-share] <name>",
+				summary: "Create a group",
+				run:     runGroupsCreate,
+			},
+			"update": {
+				usage:   "[-name name] [-description text] [-image-url url] [-share] [-office-mode] <group_id>",
+				summary: "Update a group, keeping the settings not given",
+				run:     runGroupsUpdate,
+			},
+			"destroy": {
+				usage:   "<group_id>",
+				summary: "Destroy a group you created",
+				run:     runGroupsDestroy,
+			},
+			"join": {
+				usage:   "<group_id> <share_token>",
+				summary: "Join a shared group",
+				run:     runGroupsJoin,
+			},
+			"rejoin": {
+				usage:   "<group_id>",
+				summary: "Rejoin a group you left",
+				run:     runGroupsRejoin,
+			},
+			"change-owner": {
+				usage:   "<group_id> <owner_user_id>",
+				summary: "Transfer ownership of a group you created",
+				run:     runGroupsChangeOwner,
+			},
+		},
+	}
+}
+
+func runGroupsList(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("groups list")
+	page := fs.Int("page", 1, "page of groups")
+	perPage := fs.Int("per-page", 10, "groups per page")
+	omitMembers := fs.Bool("omit-members", false, "omit the members of each group")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		query := &groupme.GroupsQuery{Page: *page, PerPage: *perPage}
+		if *omitMembers {
+			query.Omit = "memberships"
+		}
+
+		groups, err := client.IndexGroups(ctx, query)
+		if err != nil {
+			return err
+		}
+		return e.print(groups, groupColumns...)
+	})
+}
+
+func runGroupsFormer(ctx context.Context, e *env, args []string) error {
+	if _, err := parse(e.flags("groups former"), args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		groups, err := client.FormerGroups(ctx)
+		if err != nil {
+			return err
+		}
+		return e.print(groups, groupColumns...)
+	})
+}
+
+func runGroupsShow(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("groups show"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		group, err := client.ShowGroup(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(group, groupColumns...)
+	})
+}
+
+// groupSettingsFlags defines flags for the GroupSettings fields, except the name
+func groupSettingsFlags(fs *flag.FlagSet, settings *groupme.GroupSettings) {
+	fs.StringVar(&settings.Description, "description", "", "description, at most 255 characters")
+	fs.StringVar(&settings.ImageURL, "image-url", "", "image service URL of the group's avatar")
+	fs.BoolVar(&settings.Share, "share", false, "generate a share URL anyone can join with")
+}
+
+func runGroupsCreate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("groups create")
+	var settings groupme.GroupSettings
+	groupSettingsFlags(fs, &settings)
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	settings.Name = args[0]
+
+	return e.withClient(func(client *groupme.Client) error {
+		group, err := client.CreateGroup(ctx, settings)
+		if err != nil {
+			return err
+		}
+		return e.print(group, groupColumns...)
+	})
+}
+
+func runGroupsUpdate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("groups update")
+	var settings groupme.GroupSettings
+	fs.StringVar(&settings.Name, "name", "", "name, at most 140 characters")
+	groupSettingsFlags(fs, &settings)
+	fs.BoolVar(&settings.OfficeMode, "office-mode", false, "disable notifications for all members")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		// UpdateGroup sets every setting, so start from the current ones.
+		// Groups don't report office mode, so it's off unless given
+		group, err := client.ShowGroup(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		current := groupme.GroupSettings{
+			Name:        group.Name,
+			Description: group.Description,
+			ImageURL:    group.ImageURL,
+			Share:       group.ShareURL != "",
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "name":
+				current.Name = settings.Name
+			case "description":
+				current.Description = settings.Description
+			case "image-url":
+				current.ImageURL = settings.ImageURL
+			case "share":
+				current.Share = settings.Share
+			case "office-mode":
+				current.OfficeMode = settings.OfficeMode
+			}
+		})
+
+		group, err = client.UpdateGroup(ctx, args[0], current)
+		if err != nil {
+			return err
+		}
+		return e.print(group, groupColumns...)
+	})
+}
+
+func runGroupsDestroy(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("groups destroy"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		return client.DestroyGroup(ctx, args[0])
+	})
+}
+
+func runGroupsJoin(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("groups join"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		group, err := client.JoinGroup(ctx, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		return e.print(group, groupColumns...)
+	})
+}
+
+func runGroupsRejoin(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("groups rejoin"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		group, err := client.RejoinGroup(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(group, groupColumns...)
+	})
+}
+
+func runGroupsChangeOwner(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("groups change-owner"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		result, err := client.ChangeGroupOwner(ctx, groupme.ChangeOwnerRequest{GroupID: args[0], OwnerID: args[1]})
+		if err != nil {
+			return err
+		}
+		if result.Status != groupme.ChangeOwnerOk {
+			return fmt.Errorf("changing owner: %s", result.Status)
+		}
+		return e.print(result, column{"GROUP", "group_id"}, column{"OWNER", "owner_id"}, column{"STATUS", "status"})
+	})
+}
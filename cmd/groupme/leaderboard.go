@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/densestvoid/groupme"
+)
+
+func init() {
+	commands["leaderboard"] = &command{
+		summary: "List a group's most liked messages",
+		subcommands: map[string]*command{
+			"top": {
+				usage:   "[-period day|week|month] <group_id>",
+				summary: "List the most liked messages of the period",
+				run:     runLeaderboardTop,
+			},
+			"mine": {
+				usage:   "<group_id>",
+				summary: "List the messages you liked",
+				run:     runLeaderboardMine,
+			},
+			"for-me": {
+				usage:   "<group_id>",
+				summary: "List your messages others liked",
+				run:     runLeaderboardForMe,
+			},
+		},
+	}
+}
+
+func runLeaderboardTop(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("leaderboard top")
+	period := fs.String("period", "day", "period: day, week or month")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		var messages []*groupme.Message
+		switch *period {
+		case groupme.PeriodDay:
+			messages, err = client.IndexLeaderboard(ctx, args[0], groupme.PeriodDay)
+		case groupme.PeriodWeek:
+			messages, err = client.IndexLeaderboard(ctx, args[0], groupme.PeriodWeek)
+		case groupme.PeriodMonth:
+			messages, err = client.IndexLeaderboard(ctx, args[0], groupme.PeriodMonth)
+		default:
+			return fmt.Errorf("unknown period %q", *period)
+		}
+		if err != nil {
+			return err
+		}
+		return e.print(messages, messageColumns...)
+	})
+}
+
+func runLeaderboardMine(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("leaderboard mine"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		messages, err := client.MyLikesLeaderboard(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(messages, messageColumns...)
+	})
+}
+
+func runLeaderboardForMe(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("leaderboard for-me"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		messages, err := client.MyHitsLeaderboard(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(messages, messageColumns...)
+	})
+}
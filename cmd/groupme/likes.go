@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+
+	"github.com/densestvoid/groupme"
+)
+
+func init() {
+	commands["likes"] = &command{
+		summary: "Like and unlike messages",
+		subcommands: map[string]*command{
+			"like": {
+				usage:   "<conversation_id> <message_id>",
+				summary: "Like a message in a group, or direct message conversation (user_id+user_id)",
+				run:     runLikesLike,
+			},
+			"unlike": {
+				usage:   "<conversation_id> <message_id>",
+				summary: "Unlike a message in a group, or direct message conversation (user_id+user_id)",
+				run:     runLikesUnlike,
+			},
+		},
+	}
+}
+
+func runLikesLike(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("likes like"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		return client.CreateLike(ctx, args[0], args[1])
+	})
+}
+
+func runLikesUnlike(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("likes unlike"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		return client.DestroyLike(ctx, args[0], args[1])
+	})
+}
@@ -0,0 +1,207 @@
+// Command groupme uses the GroupMe API from the command line.
+//
+// Usage:
+//
+//	groupme [-output json|table|yaml] [-config file] <command> [subcommand] [flags] [arguments]
+//
+// The access token is read from the GROUPME_TOKEN environment variable,
+// or the token key of the YAML config file, which defaults to groupme/config.yaml
+// in the user config directory and may also set the default output format:
+//
+//	token: 0123456789ABCDEF
+//	output: json
+//
+// Run "groupme help" to list the commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/densestvoid/groupme"
+)
+
+// errUsage is returned by commands given invalid arguments, after printing their usage
+var errUsage = errors.New("invalid usage")
+
+// command is a groupme command, running either itself or one of its subcommands
+type command struct {
+	// usage is the flags and arguments following the command name
+	usage       string
+	summary     string
+	run         func(ctx context.Context, e *env, args []string) error
+	subcommands map[string]*command
+}
+
+// commands maps names to commands. Populated by the init functions of the command files
+var commands = map[string]*command{}
+
+// lookup returns the command at the space separated path, such as "groups list"
+func lookup(path string) *command {
+	cmds := commands
+	var cmd *command
+	for _, name := range strings.Fields(path) {
+		if cmd = cmds[name]; cmd == nil {
+			return nil
+		}
+		cmds = cmd.subcommands
+	}
+	return cmd
+}
+
+// env is the environment commands run in
+type env struct {
+	stdout, stderr io.Writer
+	getenv         func(string) string
+	// clientOptions are appended to the options of created clients
+	clientOptions []groupme.ClientOption
+
+	configPath string
+	config     *config
+	output     string
+}
+
+// flags creates the flag set of the command at the path, printing its usage
+// on errors. Every command accepts -output
+func (e *env) flags(path string) *flag.FlagSet {
+	cmd := lookup(path)
+
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.output, "output", e.output, "output format: json, table or yaml")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: groupme %s %s\n\n%s\n\n", path, cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags, which may be interspersed with the arguments,
+// checking the number of arguments is between min and max. A negative max
+// allows any number of arguments. Everything after "--" is an argument
+func parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		// fs.Parse stops at "--", consuming it
+		if parsed := len(args) - len(rest); parsed > 0 && args[parsed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// stringsFlag is a flag that may be repeated
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := &env{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(run(ctx, e, os.Args[1:]))
+}
+
+// run runs the command named by the arguments, returning the exit code
+func run(ctx context.Context, e *env, args []string) int {
+	fs := flag.NewFlagSet("groupme", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.output, "output", "", "output format: json, table or yaml (default table)")
+	fs.StringVar(&e.configPath, "config", "", "config file (default groupme/config.yaml in the user config directory)")
+	fs.Usage = func() { printHelp(e.stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := e.loadConfig(); err != nil {
+		fmt.Fprintf(e.stderr, "groupme: %v\n", err)
+		return 1
+	}
+
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		printHelp(e.stderr, fs)
+		return 2
+	}
+
+	path, cmd, args := args[0], commands[args[0]], args[1:]
+	for cmd != nil && cmd.subcommands != nil {
+		if len(args) == 0 || cmd.subcommands[args[0]] == nil {
+			printSubcommands(e.stderr, path, cmd)
+			return 2
+		}
+		path, cmd, args = path+" "+args[0], cmd.subcommands[args[0]], args[1:]
+	}
+	if cmd == nil {
+		fmt.Fprintf(e.stderr, "groupme: unknown command %q\n\n", path)
+		printHelp(e.stderr, fs)
+		return 2
+	}
+
+	if err := cmd.run(ctx, e, args); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintf(e.stderr, "groupme %s: %v\n", path, err)
+		return 1
+	}
+	return 0
+}
+
+func printHelp(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "usage: groupme [flags] <command> [subcommand] [flags] [arguments]\n\n")
+	fmt.Fprintf(w, "The access token is read from %s, or the config file.\n\nFlags:\n", tokenEnv)
+	fs.PrintDefaults()
+
+	fmt.Fprintf(w, "\nCommands:\n")
+	for _, name := range sortedNames(commands) {
+		fmt.Fprintf(w, "  %-16s %s\n", name, commands[name].summary)
+	}
+}
+
+func printSubcommands(w io.Writer, path string, cmd *command) {
+	fmt.Fprintf(w, "usage: groupme %s <subcommand> [flags] [arguments]\n\n%s\n\nSubcommands:\n", path, cmd.summary)
+	for _, name := range sortedNames(cmd.subcommands) {
+		sub := cmd.subcommands[name]
+		fmt.Fprintf(w, "  %-14s %s\n", name, sub.summary)
+	}
+}
+
+func sortedNames(cmds map[string]*command) []string {
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
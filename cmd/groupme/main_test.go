@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/densestvoid/groupme"
+	"github.com/densestvoid/groupme/groupmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// testEnv returns an env using the server, with the token in GROUPME_TOKEN
+// and an empty config file, so the user's config file isn't read
+func testEnv(t *testing.T, server *groupmetest.Server, token string) (*env, *bytes.Buffer, *bytes.Buffer) {
+	configPath := writeConfig(t, "")
+
+	var stdout, stderr bytes.Buffer
+	return &env{
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(key string) string {
+			switch key {
+			case tokenEnv:
+				return token
+			case configEnv:
+				return configPath
+			}
+			return ""
+		},
+		clientOptions: server.ClientOptions(),
+	}, &stdout, &stderr
+}
+
+func writeConfig(t *testing.T, config string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0600))
+	return path
+}
+
+func TestRun_Usage(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	e, _, stderr := testEnv(t, server, "")
+	assert.Equal(t, 2, run(context.Background(), e, nil))
+	assert.Contains(t, stderr.String(), "export")
+
+	assert.Equal(t, 2, run(context.Background(), e, []string{"unknown"}))
+	assert.Equal(t, 2, run(context.Background(), e, []string{"export"}))
+	assert.Equal(t, 1, run(context.Background(), e, []string{"export", "123"}))
+	assert.Contains(t, stderr.String(), tokenEnv)
+}
+
+func TestParse(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	verbose := fs.Bool("v", false, "")
+
+	args, err := parse(fs, []string{"a", "-v", "b", "--", "-a", "--", "-b"}, 0, -1)
+	require.NoError(t, err)
+	assert.True(t, *verbose)
+	assert.Equal(t, []string{"a", "b", "-a", "--", "-b"}, args)
+
+	_, err = parse(fs, []string{"-a"}, 0, -1)
+	assert.Error(t, err)
+}
+
+func TestRun_Export(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Team"})
+	server.AddMessage(group.ID, "1", "Hello")
+
+	dir := filepath.Join(t.TempDir(), "out")
+	e, stdout, stderr := testEnv(t, server, token)
+	require.Equal(t, 0, run(context.Background(), e, []string{"export", "-dir", dir, "-formats", "text", group.ID}), stderr.String())
+	assert.Contains(t, stdout.String(), "Exported 1 new messages of Team")
+
+	_, err := os.Stat(filepath.Join(dir, "transcript.txt"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "transcript.html"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRun_Config(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	configPath := writeConfig(t, "token: "+token+"\noutput: json\n")
+
+	e, stdout, stderr := testEnv(t, server, "")
+	require.Equal(t, 0, run(context.Background(), e, []string{"-config", configPath, "users", "me"}), stderr.String())
+
+	var me groupme.User
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &me))
+	assert.Equal(t, "Alice", me.Name)
+
+	e, _, stderr = testEnv(t, server, "")
+	assert.Equal(t, 1, run(context.Background(), e, []string{"-config", filepath.Join(t.TempDir(), "missing.yaml"), "users", "me"}))
+	assert.Contains(t, stderr.String(), "missing.yaml")
+}
+
+func TestRun_Output(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	server.AddGroup("1", groupme.GroupSettings{Name: "Team"})
+
+	e, stdout, stderr := testEnv(t, server, token)
+	require.Equal(t, 0, run(context.Background(), e, []string{"groups", "list"}), stderr.String())
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^ID\s+NAME\s+TYPE\s+MEMBERS`, lines[0])
+	assert.Regexp(t, `^1\s+Team\s+private\s+1`, lines[1])
+
+	e, stdout, stderr = testEnv(t, server, token)
+	require.Equal(t, 0, run(context.Background(), e, []string{"groups", "list", "-output", "yaml"}), stderr.String())
+	var groups []map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(stdout.Bytes(), &groups))
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Team", groups[0]["name"])
+
+	e, _, stderr = testEnv(t, server, token)
+	assert.Equal(t, 1, run(context.Background(), e, []string{"-output", "xml", "groups", "list"}))
+	assert.Contains(t, stderr.String(), "xml")
+}
+
+func TestRun_Commands(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	server.AddUser(groupme.User{ID: "2", Name: "Bob"})
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Team"})
+
+	runOK := func(args ...string) string {
+		e, stdout, stderr := testEnv(t, server, token)
+		require.Equal(t, 0, run(context.Background(), e, append([]string{"-output", "json"}, args...)), stderr.String())
+		return stdout.String()
+	}
+
+	runOK("messages", "send", group.ID, "Hello team")
+	var messages []*groupme.Message
+	require.NoError(t, json.Unmarshal([]byte(runOK("messages", "list", "-limit", "5", group.ID)), &messages))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Hello team", messages[0].Text)
+
+	runOK("likes", "like", group.ID, messages[0].ID)
+	assert.Len(t, server.Messages(group.ID)[0].FavoritedBy, 1)
+
+	runOK("members", "add", group.ID, "Bobby:2")
+	assert.Len(t, server.Group(group.ID).Members, 2)
+	assert.Contains(t, runOK("members", "add", "-wait", "1s", group.ID, "Nobody:404"), `"added": false`)
+
+	runOK("dm", "send", "2", "Hi Bob")
+	assert.Len(t, server.DirectMessages("1", "2"), 1)
+
+	var bot groupme.Bot
+	require.NoError(t, json.Unmarshal([]byte(runOK("bots", "create", group.ID, "Helper")), &bot))
+	runOK("bots", "post", bot.BotID, "Beep")
+	assert.Len(t, server.Messages(group.ID), 2)
+
+	runOK("blocks", "create", "2")
+	assert.Len(t, server.Blocks(), 1)
+	assert.Contains(t, runOK("blocks", "between", "2"), "true")
+
+	// Pictures are uploaded as is
+	picture := []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;")
+	path := filepath.Join(t.TempDir(), "picture.gif")
+	require.NoError(t, os.WriteFile(path, picture, 0600))
+	var urls groupme.PictureURL
+	require.NoError(t, json.Unmarshal([]byte(runOK("upload-picture", path)), &urls))
+	resp, err := http.Get(urls.Base)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	uploaded, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "image/gif", resp.Header.Get("Content-Type"))
+	assert.Equal(t, picture, uploaded)
+}
+
+func TestRun_MembersSync(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	server.AddUser(groupme.User{ID: "2", Name: "Bob"})
+	server.AddUser(groupme.User{ID: "3", Name: "Carol"})
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Team"}, "2")
+
+	roster := filepath.Join(t.TempDir(), "roster.csv")
+	require.NoError(t, os.WriteFile(roster, []byte("nickname,user_id\nCarol,3\n"), 0600))
+
+	e, stdout, stderr := testEnv(t, server, token)
+	require.Equal(t, 0, run(context.Background(), e, []string{"members", "sync", "-dry-run", group.ID, roster}), stderr.String())
+	assert.Regexp(t, `add\s+planned\s+Carol\s+3`, stdout.String())
+	assert.Regexp(t, `remove\s+planned\s+Bob\s+2`, stdout.String())
+	assert.Regexp(t, `protect\s+Alice\s+1`, stdout.String())
+	assert.Len(t, server.Group(group.ID).Members, 2)
+
+	e, stdout, stderr = testEnv(t, server, token)
+	require.Equal(t, 0, run(context.Background(), e, []string{"members", "sync", "-delay", "0", group.ID, roster}), stderr.String())
+	assert.Regexp(t, `add\s+done\s+Carol\s+3`, stdout.String())
+	assert.Regexp(t, `remove\s+done\s+Bob\s+2`, stdout.String())
+	assert.NotNil(t, server.Group(group.ID).GetMemberByUserID("3"))
+	assert.Nil(t, server.Group(group.ID).GetMemberByUserID("2"))
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/densestvoid/groupme"
+)
+
+var memberColumns = []column{
+	{"ID", "id"},
+	{"USER ID", "user_id"},
+	{"NICKNAME", "nickname"},
+	{"MUTED", "muted"},
+}
+
+func init() {
+	commands["members"] = &command{
+		summary: "List, add and remove group members, and change your nickname",
+		subcommands: map[string]*command{
+			"list": {
+				usage:   "<group_id>",
+				summary: "List the members of a group",
+				run:     runMembersList,
+			},
+			"add": {
+				usage: "[-wait timeout] <group_id> <nickname>:<user_id|phone_number|email>...\n\n" +
+					"Phone numbers start with + and email addresses contain @, e.g. \"Bob:+1 5555555555\"",
+				summary: "Add members to a group, printing the results ID, or with -wait whether each was added",
+				run:     runMembersAdd,
+			},
+			"results": {
+				usage:   "<group_id> <results_id>",
+				summary: "List the members added by an add, once processed",
+				run:     runMembersResults,
+			},
+			"remove": {
+				usage:   "<group_id> <membership_id>",
+				summary: "Remove a member from a group",
+				run:     runMembersRemove,
+			},
+			"sync": {
+				usage: "[-dry-run] [-delay duration] [-wait timeout] [-protect user_id]... [-remove-unmatched] [-format csv|json] <group_id> <roster_file>\n\n" +
+					"The roster lists the nickname and any of user_id, phone_number and email of every member,\n" +
+					"as CSV with a header row, or a JSON array. Entries without a user ID match members by nickname,\n" +
+					"so members are only removed from such rosters with -remove-unmatched",
+				summary: "Add and remove members so the group matches a roster",
+				run:     runMembersSync,
+			},
+			"nickname": {
+				usage:   "<group_id> <nickname>",
+				summary: "Change your nickname in a group",
+				run:     runMembersNickname,
+			},
+		},
+	}
+}
+
+func runMembersList(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("members list"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		group, err := client.ShowGroup(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(group.Members, memberColumns...)
+	})
+}
+
+// parseMember parses a "nickname:identifier" argument
+func parseMember(arg string) (*groupme.Member, error) {
+	i := strings.LastIndex(arg, ":")
+	if i <= 0 || i == len(arg)-1 {
+		return nil, fmt.Errorf("member %q is not of the form nickname:identifier", arg)
+	}
+
+	member := &groupme.Member{Nickname: arg[:i]}
+	switch identifier := arg[i+1:]; {
+	case strings.HasPrefix(identifier, "+"):
+		member.PhoneNumber = identifier
+	case strings.Contains(identifier, "@"):
+		member.Email = identifier
+	default:
+		member.UserID = identifier
+	}
+	return member, nil
+}
+
+func runMembersAdd(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("members add")
+	wait := fs.Duration("wait", 0, "wait up to this long for the results of the adds")
+	args, err := parse(fs, args, 2, -1)
+	if err != nil {
+		return err
+	}
+
+	var members []*groupme.Member
+	for _, arg := range args[1:] {
+		member, err := parseMember(arg)
+		if err != nil {
+			return err
+		}
+		members = append(members, member)
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		if *wait > 0 {
+			report, err := client.AddMembersAndWait(ctx, args[0], *wait, members...)
+			if err != nil {
+				return err
+			}
+			return e.print(addMemberRows(report),
+				column{"NICKNAME", "nickname"},
+				column{"ADDED", "added"},
+				column{"MEMBERSHIP ID", "membership_id"},
+				column{"USER ID", "user_id"},
+			)
+		}
+
+		resultsID, err := client.AddMembers(ctx, args[0], members...)
+		if err != nil {
+			return err
+		}
+		return e.print(map[string]string{"results_id": resultsID}, column{"RESULTS ID", "results_id"})
+	})
+}
+
+type addMemberRow struct {
+	Nickname     string `json:"nickname"`
+	Added        bool   `json:"added"`
+	MembershipID string `json:"membership_id,omitempty"`
+	UserID       string `json:"user_id,omitempty"`
+	GUID         string `json:"guid"`
+}
+
+// addMemberRows flattens the report into a row per requested member
+func addMemberRows(report *groupme.AddMembersReport) []addMemberRow {
+	rows := make([]addMemberRow, len(report.Results))
+	for i, result := range report.Results {
+		rows[i] = addMemberRow{Nickname: result.Requested.Nickname, GUID: result.Requested.GUID}
+		if result.Added() {
+			rows[i].Added = true
+			rows[i].MembershipID = result.Membership.ID
+			rows[i].UserID = result.Membership.UserID
+		}
+	}
+	return rows
+}
+
+func runMembersResults(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("members results"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		members, err := client.AddMembersResults(ctx, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		return e.print(members, memberColumns...)
+	})
+}
+
+func runMembersSync(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("members sync")
+	dryRun := fs.Bool("dry-run", false, "print the changes without making them")
+	delay := fs.Duration("delay", time.Second, "pause between the requests changing the group")
+	wait := fs.Duration("wait", time.Minute, "wait up to this long for the results of the adds")
+	var protected stringsFlag
+	fs.Var(&protected, "protect", "never remove the user ID. May be repeated")
+	removeUnmatched := fs.Bool("remove-unmatched", false, "remove members even if roster entries without a user ID were matched by nickname")
+	format := fs.String("format", "", "roster format: csv or json (default from the file extension)")
+	args, err := parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	roster, err := readRoster(args[1], *format)
+	if err != nil {
+		return err
+	}
+
+	options := []groupme.MemberSyncOption{
+		groupme.WithSyncDelay(*delay),
+		groupme.WithSyncAddTimeout(*wait),
+		groupme.WithSyncProtect(protected...),
+	}
+	if *dryRun {
+		options = append(options, groupme.WithSyncDryRun())
+	}
+	if *removeUnmatched {
+		options = append(options, groupme.WithSyncRemoveUnmatched())
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		report, syncErr := client.SyncMembers(ctx, args[0], roster, options...)
+		if report == nil {
+			return syncErr
+		}
+		if err := e.print(syncRows(report),
+			column{"ACTION", "action"},
+			column{"STATUS", "status"},
+			column{"NICKNAME", "nickname"},
+			column{"USER ID", "user_id"},
+			column{"IDENTIFIER", "identifier"},
+		); err != nil {
+			return err
+		}
+		return syncErr
+	})
+}
+
+func readRoster(path, format string) ([]*groupme.Member, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if format == "" {
+		format = "csv"
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = "json"
+		}
+	}
+
+	var roster []*groupme.Member
+	switch format {
+	case "csv":
+		roster, err = groupme.ReadRosterCSV(f)
+	case "json":
+		roster, err = groupme.ReadRosterJSON(f)
+	default:
+		return nil, fmt.Errorf("unknown roster format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return roster, nil
+}
+
+type syncRow struct {
+	Action     string `json:"action"`
+	Status     string `json:"status"`
+	Nickname   string `json:"nickname"`
+	UserID     string `json:"user_id,omitempty"`
+	Identifier string `json:"identifier,omitempty"`
+}
+
+// syncRows flattens the report into a row per change, then the unchanged members
+func syncRows(report *groupme.MemberSyncReport) []syncRow {
+	pending := "planned"
+	if !report.DryRun {
+		pending = "not done"
+	}
+
+	var rows []syncRow
+	for i, entry := range report.Plan.Add {
+		row := syncRow{Action: "add", Status: pending, Nickname: entry.Nickname, UserID: entry.UserID}
+		switch {
+		case entry.PhoneNumber != "":
+			row.Identifier = entry.PhoneNumber
+		case entry.Email != "":
+			row.Identifier = entry.Email
+		}
+		if report.Adds != nil {
+			row.Status = "failed"
+			if result := report.Adds.Results[i]; result.Added() {
+				row.Status = "done"
+				row.UserID = result.Membership.UserID
+			}
+		}
+		rows = append(rows, row)
+	}
+	for i, member := range report.Plan.Remove {
+		status := pending
+		if i < len(report.Removed) {
+			status = "done"
+		}
+		rows = append(rows, syncRow{Action: "remove", Status: status, Nickname: member.Nickname, UserID: member.UserID})
+	}
+	for _, member := range report.Plan.Protected {
+		rows = append(rows, syncRow{Action: "protect", Nickname: member.Nickname, UserID: member.UserID})
+	}
+	for _, member := range report.Plan.Keep {
+		rows = append(rows, syncRow{Action: "keep", Nickname: member.Nickname, UserID: member.UserID})
+	}
+	return rows
+}
+
+func runMembersRemove(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("members remove"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		return client.RemoveMember(ctx, args[0], args[1])
+	})
+}
+
+func runMembersNickname(ctx context.Context, e *env, args []string) error {
+	args, err := parse(e.flags("members nickname"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		member, err := client.UpdateMember(ctx, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		return e.print(member, memberColumns...)
+	})
+}
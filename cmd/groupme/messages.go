@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/densestvoid/groupme"
+)
+
+var messageColumns = []column{
+	{"ID", "id"},
+	{"CREATED", "created_at"},
+	{"NAME", "name"},
+	{"TEXT", "text"},
+	{"LIKES", "favorited_by"},
+}
+
+func init() {
+	commands["messages"] = &command{
+		summary: "List and send group messages",
+		subcommands: map[string]*command{
+			"list": {
+				usage:   "[-before id] [-since id] [-after id] [-limit n] <group_id>",
+				summary: "List a group's messages, newest first unless -after is given",
+				run:     runMessagesList,
+			},
+			"send": {
+				usage:   "[-image-url url] <group_id> <text>",
+				summary: "Send a message to a group",
+				run:     runMessagesSend,
+			},
+		},
+	}
+}
+
+func runMessagesList(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("messages list")
+	var query groupme.IndexMessagesQuery
+	fs.StringVar(&query.BeforeID, "before", "", "list messages before this message ID")
+	fs.StringVar(&query.SinceID, "since", "", "list the newest messages after this message ID")
+	fs.StringVar(&query.AfterID, "after", "", "list the messages immediately after this message ID")
+	fs.IntVar(&query.Limit, "limit", 20, "number of messages, at most 100")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		resp, err := client.IndexMessages(ctx, args[0], &query)
+		if errors.Is(err, groupme.ErrNotModified) {
+			return e.print([]*groupme.Message{}, messageColumns...)
+		}
+		if err != nil {
+			return err
+		}
+		return e.print(resp.Messages, messageColumns...)
+	})
+}
+
+// imageAttachments returns an image attachment for the URL, if any
+func imageAttachments(imageURL string) []*groupme.Attachment {
+	if imageURL == "" {
+		return nil
+	}
+	return []*groupme.Attachment{{Type: groupme.Image, URL: imageURL}}
+}
+
+func runMessagesSend(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("messages send")
+	imageURL := fs.String("image-url", "", "image service URL of a picture to attach")
+	args, err := parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		msg, err := client.CreateMessage(ctx, args[0], &groupme.Message{
+			Text:        args[1],
+			Attachments: imageAttachments(*imageURL),
+		})
+		if err != nil {
+			return err
+		}
+		return e.print(msg, messageColumns...)
+	})
+}
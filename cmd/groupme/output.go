@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	outputJSON  = "json"
+	outputTable = "table"
+	outputYAML  = "yaml"
+)
+
+// column is a table column, showing the value at the dotted JSON path,
+// such as "messages.count"
+type column struct {
+	header string
+	path   string
+}
+
+// print writes the value in the output format. Tables show the columns
+// of the value, or of each element if it's a list
+func (e *env) print(v interface{}, columns ...column) error {
+	// Formatting through JSON uses the API's field names in every format
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	switch e.output {
+	case outputJSON:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, jsonBytes, "", "  "); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(e.stdout, indented.String())
+		return err
+
+	case outputYAML:
+		var generic interface{}
+		if err := json.Unmarshal(jsonBytes, &generic); err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(e.stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(generic); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	case outputTable, "":
+		var generic interface{}
+		if err := json.Unmarshal(jsonBytes, &generic); err != nil {
+			return err
+		}
+		return e.printTable(generic, columns)
+
+	default:
+		return fmt.Errorf("unknown output format %q", e.output)
+	}
+}
+
+func (e *env) printTable(v interface{}, columns []column) error {
+	rows, ok := v.([]interface{})
+	if !ok {
+		if v == nil {
+			return nil
+		}
+		rows = []interface{}{v}
+	}
+
+	// Scalars and objects without columns print every key
+	if len(columns) == 0 {
+		if object, ok := v.(map[string]interface{}); ok {
+			return e.printObject(object)
+		}
+		for _, row := range rows {
+			fmt.Fprintln(e.stdout, formatValue(row))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = formatValue(valueAt(row, col.path))
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+func (e *env) printObject(object map[string]interface{}) error {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\n", key, formatValue(object[key]))
+	}
+	return w.Flush()
+}
+
+// valueAt returns the value at the dotted path of a decoded JSON object
+func valueAt(v interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, ".") {
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = object[key]
+	}
+	return v
+}
+
+// formatValue formats a decoded JSON value for a table cell. Lists of
+// scalars are comma separated, lists of objects are counted
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.ReplaceAll(v, "\n", " ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, element := range v {
+			if _, ok := element.(map[string]interface{}); ok {
+				return fmt.Sprint(len(v))
+			}
+			values[i] = formatValue(element)
+		}
+		return strings.Join(values, ",")
+	case map[string]interface{}:
+		jsonBytes, _ := json.Marshal(v)
+		return string(jsonBytes)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // Register decoders of the picture formats the image service accepts
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/densestvoid/groupme"
+)
+
+func init() {
+	commands["upload-picture"] = &command{
+		usage:   "[-encoding png|jpeg] <file|url>",
+		summary: "Upload a PNG, JPEG, GIF or WebP picture to the image service, for use in messages and avatars",
+		run:     runUploadPicture,
+	}
+}
+
+func runUploadPicture(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("upload-picture")
+	encoding := fs.String("encoding", "", "re-encode the picture as png or jpeg instead of uploading it as is")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *encoding != "" && *encoding != groupme.PictureEncodingPNG && *encoding != groupme.PictureEncodingJPEG {
+		return fmt.Errorf("unknown encoding %q", *encoding)
+	}
+
+	remote := strings.HasPrefix(args[0], "http://") || strings.HasPrefix(args[0], "https://")
+	if remote && *encoding != "" {
+		return fmt.Errorf("-encoding needs a file")
+	}
+
+	var img image.Image
+	if *encoding != "" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		img, _, err = image.Decode(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		var urls groupme.PictureURL
+		var err error
+		switch {
+		case img != nil:
+			urls, err = client.UploadPicture(ctx, img, groupme.PictureEncoding(*encoding))
+		case remote:
+			urls, err = client.UploadPictureURL(ctx, args[0])
+		default:
+			urls, err = client.UploadPictureFile(ctx, args[0])
+		}
+		if err != nil {
+			return err
+		}
+		return e.print(urls, column{"URL", "Base"}, column{"PREVIEW", "Preview"}, column{"LARGE", "Large"}, column{"AVATAR", "Avatar"})
+	})
+}
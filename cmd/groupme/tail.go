@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/densestvoid/groupme"
+)
+
+// tailTracked is how many of the latest printed messages have their likes followed
+const tailTracked = 100
+
+// tailMaxBackoff is the longest wait between polls after failures
+const tailMaxBackoff = time.Minute
+
+func init() {
+	commands["tail"] = &command{
+		usage: "[-n count] [-push] [-interval duration] [-user id|name]... [-match regexp] [-sender user,bot,system] [-color auto|always|never] <group_id>\n\n" +
+			"With -output json, each message is printed as a line of JSON, again whenever its likes change",
+		summary: "Print a group's new messages as they arrive, and their likes",
+		run:     runTail,
+	}
+}
+
+// tailFilter selects the messages tail prints
+type tailFilter struct {
+	// users are user IDs or names, any of which the sender must match
+	users       []string
+	match       *regexp.Regexp
+	senderTypes map[string]bool
+}
+
+func (f tailFilter) matches(msg *groupme.Message) bool {
+	if len(f.senderTypes) > 0 && !f.senderTypes[string(msg.SenderType)] {
+		return false
+	}
+	if f.match != nil && !f.match.MatchString(msg.Text) {
+		return false
+	}
+	if len(f.users) == 0 {
+		return true
+	}
+	for _, user := range f.users {
+		if user == msg.SenderID || user == msg.UserID || strings.EqualFold(user, msg.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func runTail(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("tail")
+	count := fs.Int("n", 10, "number of recent messages to print first")
+	push := fs.Bool("push", false, "follow with the push service instead of polling. Only likes of your own messages are pushed")
+	interval := fs.Duration("interval", 3*time.Second, "polling interval")
+	var users stringsFlag
+	fs.Var(&users, "user", "only print messages sent by the user ID or name. May be repeated")
+	match := fs.String("match", "", "only print messages whose text matches the regular expression")
+	senders := fs.String("sender", "", "only print messages of the comma separated sender types: user, bot or system")
+	color := fs.String("color", "auto", "colorize output: auto, always or never")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("invalid -interval %s: must be positive", *interval)
+	}
+
+	t := &tailer{
+		e:       e,
+		groupID: args[0],
+		filter:  tailFilter{users: users},
+		likes:   map[string]int{},
+	}
+	if *match != "" {
+		if t.filter.match, err = regexp.Compile(*match); err != nil {
+			return fmt.Errorf("invalid -match: %w", err)
+		}
+	}
+	if *senders != "" {
+		t.filter.senderTypes = map[string]bool{}
+		for _, sender := range strings.Split(*senders, ",") {
+			switch sender = strings.TrimSpace(sender); sender {
+			case string(groupme.SenderTypeUser), string(groupme.SenderTypeBot), string(groupme.SenderTypeSystem):
+				t.filter.senderTypes[sender] = true
+			default:
+				return fmt.Errorf("unknown sender type %q", sender)
+			}
+		}
+	}
+	switch *color {
+	case "always":
+		t.color = true
+	case "auto":
+		t.color = e.getenv("NO_COLOR") == "" && isTerminal(e.stdout)
+	case "never":
+	default:
+		return fmt.Errorf("unknown -color %q", *color)
+	}
+	if e.output == outputYAML {
+		return errors.New("tail supports table and json output")
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		t.client = client
+		if err := t.recent(ctx, *count); err != nil {
+			return err
+		}
+
+		if *push {
+			err = t.push(ctx)
+		} else {
+			err = t.poll(ctx, *interval)
+		}
+		// Interrupting is the usual way to stop
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	})
+}
+
+// isTerminal reports whether w is a terminal
+func isTerminal(w interface{}) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// tailer prints a group's messages, following their likes
+type tailer struct {
+	e       *env
+	client  *groupme.Client
+	groupID string
+	filter  tailFilter
+	color   bool
+
+	// lastID is the newest message seen
+	lastID string
+	// likes holds the like counts of the tracked messages, whose IDs are
+	// in tracked, oldest first
+	likes   map[string]int
+	tracked []string
+}
+
+// recent prints the latest count messages, and starts following after them
+func (t *tailer) recent(ctx context.Context, count int) error {
+	limit := count
+	if limit < 1 {
+		limit = 1
+	}
+	resp, err := t.client.IndexMessages(ctx, t.groupID, &groupme.IndexMessagesQuery{Limit: limit})
+	if errors.Is(err, groupme.ErrNotModified) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	messages := resp.Messages
+	if len(messages) > 0 {
+		t.lastID = messages[0].ID
+	}
+	if count < 1 {
+		return nil
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		t.message(messages[i])
+	}
+	return nil
+}
+
+// poll prints new messages and like changes every interval, until the
+// context is done. Failed polls are reported on stderr, and the wait
+// doubles after each, up to tailMaxBackoff
+func (t *tailer) poll(ctx context.Context, interval time.Duration) error {
+	wait := interval
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		err := t.pollOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			wait = interval
+			continue
+		}
+
+		if wait *= 2; wait > tailMaxBackoff {
+			wait = tailMaxBackoff
+		}
+		if wait < interval {
+			wait = interval
+		}
+		fmt.Fprintf(t.e.stderr, "tail: %v (retrying in %s)\n", err, wait)
+	}
+}
+
+// pollOnce prints the messages after the last one seen, and like changes
+func (t *tailer) pollOnce(ctx context.Context) error {
+	it := t.client.IterateMessages(t.groupID, &groupme.MessageIteratorOptions{
+		Direction: groupme.IterateForward,
+		StartID:   t.lastID,
+	})
+	for it.Next(ctx) {
+		t.message(it.Message())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	if len(t.tracked) == 0 {
+		return nil
+	}
+	resp, err := t.client.IndexMessages(ctx, t.groupID, &groupme.IndexMessagesQuery{Limit: tailTracked})
+	if errors.Is(err, groupme.ErrNotModified) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := len(resp.Messages) - 1; i >= 0; i-- {
+		t.liked(resp.Messages[i])
+	}
+	return nil
+}
+
+// push prints messages and likes delivered by the push service,
+// until the context is done
+func (t *tailer) push(ctx context.Context) error {
+	me, err := t.client.MyUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := t.e.token()
+	if err != nil {
+		return err
+	}
+	pushClient := groupme.NewPushClient(token, groupme.WithPushClientOptions(t.e.clientOptions...))
+	pushClient.SubscribeUser(me.ID)
+
+	done := make(chan error, 1)
+	go func() { done <- pushClient.Run(ctx) }()
+
+	for event := range pushClient.Events() {
+		if event.Message == nil || event.Message.GroupID != t.groupID {
+			continue
+		}
+
+		switch event.Type {
+		case groupme.PushLineCreate:
+			// Skip messages printed before the push client connected
+			if t.lastID == "" || groupme.CompareIDs(event.Message.ID, t.lastID) > 0 {
+				t.message(event.Message)
+			}
+		case groupme.PushLikeCreate:
+			t.liked(event.Message)
+		}
+	}
+	return <-done
+}
+
+// message prints a new message and starts tracking its likes
+func (t *tailer) message(msg *groupme.Message) {
+	if t.lastID == "" || groupme.CompareIDs(msg.ID, t.lastID) > 0 {
+		t.lastID = msg.ID
+	}
+	if !t.filter.matches(msg) {
+		return
+	}
+
+	t.likes[msg.ID] = len(msg.FavoritedBy)
+	t.tracked = append(t.tracked, msg.ID)
+	if len(t.tracked) > tailTracked {
+		delete(t.likes, t.tracked[0])
+		t.tracked = t.tracked[1:]
+	}
+
+	t.print(msg, "")
+}
+
+// liked prints a tracked message again if its like count changed
+func (t *tailer) liked(msg *groupme.Message) {
+	count, ok := t.likes[msg.ID]
+	if !ok || count == len(msg.FavoritedBy) {
+		return
+	}
+	t.likes[msg.ID] = len(msg.FavoritedBy)
+
+	t.print(msg, t.paint("♥ ", ansiRed))
+}
+
+func (t *tailer) print(msg *groupme.Message, prefix string) {
+	if t.e.output == outputJSON {
+		jsonBytes, err := json.Marshal(msg)
+		if err == nil {
+			fmt.Fprintln(t.e.stdout, string(jsonBytes))
+		}
+		return
+	}
+
+	fmt.Fprintln(t.e.stdout, prefix+t.render(msg))
+}
+
+/*//////// Rendering ////////*/
+
+// ANSI escape codes
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+)
+
+// nameColors are the ANSI colors of sender names, chosen by sender ID
+var nameColors = []string{"\x1b[32m", "\x1b[33m", "\x1b[34m", "\x1b[35m", "\x1b[36m", "\x1b[91m", "\x1b[92m", "\x1b[94m"}
+
+func (t *tailer) paint(s, code string) string {
+	if !t.color || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// render formats a message as a line: time, sender, text with mentions
+// highlighted, pictures and like count
+func (t *tailer) render(msg *groupme.Message) string {
+	var b strings.Builder
+	b.WriteString(t.paint(msg.CreatedAt.ToTime().Local().Format("15:04:05"), ansiDim))
+	b.WriteString(" ")
+
+	name := msg.Name
+	switch msg.SenderType {
+	case groupme.SenderTypeBot:
+		name += " [bot]"
+	case groupme.SenderTypeSystem:
+		name = "*"
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(msg.SenderID))
+	b.WriteString(t.paint(name, ansiBold+nameColors[hash.Sum32()%uint32(len(nameColors))]))
+	b.WriteString(": ")
+
+	text := t.renderMentions(msg)
+	if msg.SenderType == groupme.SenderTypeSystem {
+		text = t.paint(text, ansiDim)
+	}
+	b.WriteString(text)
+
+	for _, url := range msg.PictureURLs() {
+		b.WriteString(" ")
+		b.WriteString(t.paint("["+url+"]", ansiDim))
+	}
+	if likes := len(msg.FavoritedBy); likes > 0 {
+		b.WriteString(" ")
+		b.WriteString(t.paint(fmt.Sprintf("♥ %d", likes), ansiRed))
+	}
+	return b.String()
+}
+
+// renderMentions returns the message text on one line, with mentions in bold
+func (t *tailer) renderMentions(msg *groupme.Message) string {
+	text := msg.Text
+	if t.color {
+		text = msg.RenderMentions(func(span groupme.MentionSpan) string {
+			return t.paint(span.Text, ansiBold)
+		}, func(s string) string { return s })
+	}
+	return strings.ReplaceAll(text, "\n", " ")
+}
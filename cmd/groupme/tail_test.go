@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/densestvoid/groupme/groupmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedBuffer is a bytes.Buffer safe to read while tail writes
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// startTail runs tail with the arguments until the returned function is called
+func startTail(t *testing.T, server *groupmetest.Server, token string, args ...string) (*lockedBuffer, func()) {
+	e, _, stderr := testEnv(t, server, token)
+	stdout := &lockedBuffer{}
+	e.stdout = stdout
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() { done <- run(ctx, e, append([]string{"tail"}, args...)) }()
+
+	return stdout, func() {
+		cancel()
+		assert.Equal(t, 0, <-done, stderr.String())
+	}
+}
+
+func TestRun_TailPoll(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	aliceToken := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	bobToken := server.AddUser(groupme.User{ID: "2", Name: "Bob"})
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Team"}, "2")
+	server.AddMessage(group.ID, "1", "Old news")
+	server.AddMessage(group.ID, "1", "Recent news")
+
+	stdout, stop := startTail(t, server, aliceToken, "-n", "1", "-interval", "10ms", "-user", "alice", group.ID)
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "Recent news") }, time.Second, 10*time.Millisecond)
+
+	server.AddMessage(group.ID, "2", "From Bob")
+	msg := server.AddMessage(group.ID, "1", "From Alice")
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "From Alice") }, time.Second, 10*time.Millisecond)
+
+	bob := groupme.NewClient(bobToken, server.ClientOptions()...)
+	defer bob.Close()
+	require.NoError(t, bob.CreateLike(context.Background(), group.ID, msg.ID))
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "♥ 1") }, time.Second, 10*time.Millisecond)
+	stop()
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^\d\d:\d\d:\d\d Alice: Recent news$`, lines[0])
+	assert.Regexp(t, `^\d\d:\d\d:\d\d Alice: From Alice$`, lines[1])
+	assert.Regexp(t, `^♥ \d\d:\d\d:\d\d Alice: From Alice ♥ 1$`, lines[2])
+}
+
+// unavailableTransport responds 503 Service Unavailable while down is set
+type unavailableTransport struct {
+	down int32
+}
+
+func (t *unavailableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.LoadInt32(&t.down) == 1 {
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     http.Header{},
+			Request:    req,
+		}, nil
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRun_TailPollFailure(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Team"})
+
+	transport := &unavailableTransport{}
+	e, _, _ := testEnv(t, server, token)
+	e.clientOptions = append(e.clientOptions, groupme.WithHTTPClient(&http.Client{Transport: transport}))
+	stdout, stderr := &lockedBuffer{}, &lockedBuffer{}
+	e.stdout, e.stderr = stdout, stderr
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() { done <- run(ctx, e, []string{"tail", "-interval", "10ms", group.ID}) }()
+
+	// Wait for the recent messages to have been fetched
+	server.AddMessage(group.ID, "1", "Before")
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "Before") }, time.Second, 10*time.Millisecond)
+
+	// Failed polls are reported, and polling continues
+	atomic.StoreInt32(&transport.down, 1)
+	require.Eventually(t, func() bool { return strings.Contains(stderr.String(), "503") }, time.Second, 10*time.Millisecond)
+	atomic.StoreInt32(&transport.down, 0)
+
+	server.AddMessage(group.ID, "1", "After")
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "After") }, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.Equal(t, 0, <-done)
+}
+
+func TestRun_TailInterval(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Team"})
+
+	for _, interval := range []string{"0", "-1s"} {
+		e, _, stderr := testEnv(t, server, token)
+		assert.Equal(t, 1, run(context.Background(), e, []string{"tail", "-interval", interval, group.ID}))
+		assert.Contains(t, stderr.String(), "invalid -interval")
+	}
+}
+
+func TestRun_TailPush(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+
+	token := server.AddUser(groupme.User{ID: "1", Name: "Alice"})
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Team"})
+
+	stdout, stop := startTail(t, server, token, "-n", "0", "-push", "-match", "^ping", "-sender", "user", group.ID)
+
+	// Messages sent before the push client subscribes are missed, so keep sending
+	i := 0
+	require.Eventually(t, func() bool {
+		i++
+		server.AddMessage(group.ID, "1", "ignored")
+		server.AddMessage(group.ID, "1", fmt.Sprintf("ping %d", i))
+		return strings.Contains(stdout.String(), "ping")
+	}, 2*time.Second, 20*time.Millisecond)
+	stop()
+
+	assert.NotContains(t, stdout.String(), "ignored")
+}
+
+func TestTailer_Render(t *testing.T) {
+	msg := &groupme.Message{
+		CreatedAt:  groupme.FromTime(time.Date(2021, 1, 2, 15, 4, 5, 0, time.Local)),
+		SenderID:   "7",
+		SenderType: groupme.SenderTypeBot,
+		Name:       "Helper",
+		Text:       "hi @Bob 😀\nbye",
+		ImageURL:   "https://i.groupme.com/1",
+		Attachments: []*groupme.Attachment{
+			{Type: groupme.Mentions, UserIDs: []string{"2"}, Loci: [][]int{{3, 4}}},
+		},
+		FavoritedBy: []string{"1", "2"},
+	}
+
+	plain := &tailer{}
+	assert.Equal(t, "15:04:05 Helper [bot]: hi @Bob 😀 bye [https://i.groupme.com/1] ♥ 2", plain.render(msg))
+
+	colored := &tailer{color: true}
+	assert.Contains(t, colored.render(msg), "hi "+ansiBold+"@Bob"+ansiReset+" 😀 bye")
+}
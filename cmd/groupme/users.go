@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/densestvoid/groupme"
+)
+
+var userColumns = []column{
+	{"ID", "id"},
+	{"NAME", "name"},
+	{"EMAIL", "email"},
+	{"PHONE NUMBER", "phone_number"},
+	{"SMS", "sms"},
+}
+
+func init() {
+	commands["users"] = &command{
+		summary: "Show and update your user",
+		subcommands: map[string]*command{
+			"me": {
+				summary: "Show your user",
+				run:     runUsersMe,
+			},
+			"update": {
+				usage:   "[-avatar-url url] [-name name] [-email email] [-zip-code code]",
+				summary: "Update your user, keeping the settings not given",
+				run:     runUsersUpdate,
+			},
+		},
+	}
+	commands["sms-mode"] = &command{
+		summary: "Enable and disable SMS mode",
+		subcommands: map[string]*command{
+			"create": {
+				usage:   "[-registration-id id] <duration_hours>",
+				summary: "Receive messages by SMS for 1 to 48 hours, suppressing push notifications to the registration",
+				run:     runSMSModeCreate,
+			},
+			"delete": {
+				summary: "Disable SMS mode",
+				run:     runSMSModeDelete,
+			},
+		},
+	}
+}
+
+func runUsersMe(ctx context.Context, e *env, args []string) error {
+	if _, err := parse(e.flags("users me"), args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		me, err := client.MyUser(ctx)
+		if err != nil {
+			return err
+		}
+		return e.print(me, userColumns...)
+	})
+}
+
+func runUsersUpdate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("users update")
+	avatarURL := fs.String("avatar-url", "", "image URL of your avatar")
+	name := fs.String("name", "", "name, of the form FirstName LastName")
+	email := fs.String("email", "", "email address")
+	zipCode := fs.String("zip-code", "", "zip code")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		me, err := client.MyUser(ctx)
+		if err != nil {
+			return err
+		}
+
+		settings := groupme.UserSettings{
+			AvatarURL: me.AvatarURL,
+			Name:      me.Name,
+			Email:     me.Email,
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "avatar-url":
+				settings.AvatarURL = *avatarURL
+			case "name":
+				settings.Name = *name
+			case "email":
+				settings.Email = *email
+			case "zip-code":
+				settings.ZipCode = *zipCode
+			}
+		})
+
+		updated, err := client.UpdateMyUser(ctx, settings)
+		if err != nil {
+			return err
+		}
+		return e.print(updated, userColumns...)
+	})
+}
+
+func runSMSModeCreate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("sms-mode create")
+	registrationID := fs.String("registration-id", "", "push notification registration to suppress")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	duration, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", args[0])
+	}
+	if *registrationID == "" {
+		registrationID = nil
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		return client.CreateSMSMode(ctx, duration, registrationID)
+	})
+}
+
+func runSMSModeDelete(ctx context.Context, e *env, args []string) error {
+	if _, err := parse(e.flags("sms-mode delete"), args, 0, 0); err != nil {
+		return err
+	}
+
+	return e.withClient(func(client *groupme.Client) error {
+		return client.DeleteSMSMode(ctx)
+	})
+}
@@ -0,0 +1,200 @@
+package groupme
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Conversation is a group or direct message chat, so tools can treat a
+// Group from IndexGroups and a Chat from IndexChats uniformly
+type Conversation interface {
+	// ID is the group ID, or the other user's ID for direct messages
+	ID() string
+	// Name is the group name, or the other user's name for direct messages
+	Name() string
+	// Messages returns the page of messages before the cursor message ID,
+	// newest first, or the newest page if the cursor is empty.
+	// Returns an empty page at the start of the history
+	Messages(ctx context.Context, cursor string) ([]*Message, error)
+	// Send creates the message in the conversation
+	Send(ctx context.Context, m *Message) (*Message, error)
+	// Like likes the message as the client's user
+	Like(ctx context.Context, messageID string) error
+	// Unlike unlikes the message as the client's user
+	Unlike(ctx context.Context, messageID string) error
+	// Members returns the participants. Direct message members
+	// have the user's ID, name and avatar
+	Members(ctx context.Context) ([]*Member, error)
+}
+
+// GroupConversation returns the group as a Conversation
+func (c *Client) GroupConversation(group *Group) Conversation {
+	return &groupConversation{client: c, group: group}
+}
+
+// ChatConversation returns the direct message chat as a Conversation
+func (c *Client) ChatConversation(chat *Chat) Conversation {
+	conversation := &chatConversation{client: c, otherUser: chat.OtherUser}
+	if chat.LastMessage != nil && chat.LastMessage.ConversationID != "" {
+		conversation.conversationID = chat.LastMessage.ConversationID
+	}
+	return conversation
+}
+
+// DirectMessageConversation returns the direct message chat with the other user as a Conversation
+func (c *Client) DirectMessageConversation(otherUserID string) Conversation {
+	return &chatConversation{client: c, otherUser: User{ID: otherUserID}}
+}
+
+/*//////// Groups ////////*/
+
+type groupConversation struct {
+	client *Client
+	group  *Group
+}
+
+func (g *groupConversation) ID() string {
+	return g.group.ID
+}
+
+func (g *groupConversation) Name() string {
+	return g.group.Name
+}
+
+func (g *groupConversation) Messages(ctx context.Context, cursor string) ([]*Message, error) {
+	resp, err := g.client.IndexMessages(ctx, g.group.ID, &IndexMessagesQuery{BeforeID: cursor, Limit: maxMessagesLimit})
+	if errors.Is(err, ErrNotModified) {
+		return []*Message{}, nil
+	}
+	return resp.Messages, err
+}
+
+func (g *groupConversation) Send(ctx context.Context, m *Message) (*Message, error) {
+	return g.client.CreateMessage(ctx, g.group.ID, m)
+}
+
+func (g *groupConversation) Like(ctx context.Context, messageID string) error {
+	return g.client.CreateLike(ctx, g.group.ID, messageID)
+}
+
+func (g *groupConversation) Unlike(ctx context.Context, messageID string) error {
+	return g.client.DestroyLike(ctx, g.group.ID, messageID)
+}
+
+// Members fetches the group, as listed groups may omit or have stale members
+func (g *groupConversation) Members(ctx context.Context) ([]*Member, error) {
+	group, err := g.client.ShowGroup(ctx, g.group.ID)
+	if err != nil {
+		return nil, err
+	}
+	return group.Members, nil
+}
+
+/*//////// Direct Messages ////////*/
+
+type chatConversation struct {
+	client    *Client
+	otherUser User
+
+	mu             sync.Mutex
+	me             *User
+	conversationID string
+}
+
+func (c *chatConversation) ID() string {
+	return c.otherUser.ID
+}
+
+func (c *chatConversation) Name() string {
+	return c.otherUser.Name
+}
+
+func (c *chatConversation) Messages(ctx context.Context, cursor string) ([]*Message, error) {
+	resp, err := c.client.IndexDirectMessages(ctx, c.otherUser.ID, &IndexDirectMessagesQuery{BeforeID: cursor})
+	if errors.Is(err, ErrNotModified) {
+		return []*Message{}, nil
+	}
+	return resp.Messages, err
+}
+
+func (c *chatConversation) Send(ctx context.Context, m *Message) (*Message, error) {
+	// Leave the caller's message, which may be sent to other conversations, as is
+	msg := *m
+	msg.RecipientID = c.otherUser.ID
+	return c.client.CreateDirectMessage(ctx, &msg)
+}
+
+func (c *chatConversation) Like(ctx context.Context, messageID string) error {
+	conversationID, err := c.likeConversationID(ctx)
+	if err != nil {
+		return err
+	}
+	return c.client.CreateLike(ctx, conversationID, messageID)
+}
+
+func (c *chatConversation) Unlike(ctx context.Context, messageID string) error {
+	conversationID, err := c.likeConversationID(ctx)
+	if err != nil {
+		return err
+	}
+	return c.client.DestroyLike(ctx, conversationID, messageID)
+}
+
+func (c *chatConversation) Members(ctx context.Context) ([]*Member, error) {
+	me, err := c.myUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*Member{userMember(*me), userMember(c.otherUser)}, nil
+}
+
+// myUser fetches the client's user once
+func (c *chatConversation) myUser(ctx context.Context) (*User, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.me == nil {
+		me, err := c.client.MyUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.me = me
+	}
+	return c.me, nil
+}
+
+// likeConversationID returns the conversation ID likes use, fetching the client's user if unknown
+func (c *chatConversation) likeConversationID(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	conversationID := c.conversationID
+	c.mu.Unlock()
+	if conversationID != "" {
+		return conversationID, nil
+	}
+
+	me, err := c.myUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	conversationID = ConversationID(me.ID, c.otherUser.ID)
+	c.mu.Lock()
+	c.conversationID = conversationID
+	c.mu.Unlock()
+	return conversationID, nil
+}
+
+func userMember(u User) *Member {
+	imageURL := u.AvatarURL
+	if imageURL == "" {
+		imageURL = u.ImageURL
+	}
+
+	return &Member{
+		UserID:   u.ID,
+		Nickname: u.Name,
+		ImageURL: imageURL,
+	}
+}
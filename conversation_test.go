@@ -0,0 +1,59 @@
+package groupme_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/densestvoid/groupme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversation(t *testing.T) {
+	team := newTeam(t, "2")
+	alice, server, group := team.alice, team.server, team.group
+	ctx := context.Background()
+
+	_, err := team.client(t, "2").CreateDirectMessage(ctx, &groupme.Message{RecipientID: "1", Text: "Hi Alice"})
+	require.NoError(t, err)
+
+	groups, err := alice.IndexGroups(ctx, nil)
+	require.NoError(t, err)
+	chats, err := alice.IndexChats(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Len(t, chats, 1)
+
+	conversations := []groupme.Conversation{
+		alice.GroupConversation(groups[0]),
+		alice.ChatConversation(chats[0]),
+		alice.DirectMessageConversation("2"),
+	}
+	for _, conversation := range conversations {
+		msg := &groupme.Message{Text: "Hello " + conversation.Name()}
+		sent, err := conversation.Send(ctx, msg)
+		require.NoError(t, err)
+		assert.Empty(t, msg.RecipientID, "the message sent isn't modified")
+
+		messages, err := conversation.Messages(ctx, "")
+		require.NoError(t, err)
+		require.NotEmpty(t, messages)
+		assert.Equal(t, sent.ID, messages[0].ID)
+
+		older, err := conversation.Messages(ctx, messages[len(messages)-1].ID)
+		require.NoError(t, err)
+		assert.Empty(t, older)
+
+		require.NoError(t, conversation.Like(ctx, sent.ID))
+		members, err := conversation.Members(ctx)
+		require.NoError(t, err)
+		assert.Len(t, members, 2)
+		require.NoError(t, conversation.Unlike(ctx, sent.ID))
+	}
+
+	assert.Equal(t, group.ID, conversations[0].ID())
+	assert.Equal(t, "2", conversations[1].ID())
+	assert.Equal(t, "Bob", conversations[1].Name())
+	assert.Len(t, server.Messages(group.ID), 1)
+	assert.Len(t, server.DirectMessages("1", "2"), 3)
+}
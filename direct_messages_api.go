@@ -22,6 +22,27 @@ const (
 	createDirectMessageEndpoint = directMessagesEndpointRoot // POST
 )
 
+// ConversationID returns the conversation ID of the direct messages between
+// two users, as used by CreateLike and DestroyLike: the smaller user ID, a
+// plus sign, and the larger user ID
+func ConversationID(userID, otherUserID string) string {
+	if CompareIDs(userID, otherUserID) > 0 {
+		userID, otherUserID = otherUserID, userID
+	}
+	return userID + "+" + otherUserID
+}
+
+// directMessageAttachmentTypes are the attachment types supported in direct
+// messages. Mentions, splits, polls and events only exist in groups
+var directMessageAttachmentTypes = map[attachmentType]bool{
+	Image:    true,
+	Location: true,
+	Emoji:    true,
+	Reply:    true,
+	File:     true,
+	Video:    true,
+}
+
 /*//////// API Requests ////////*/
 
 // IndexDirectMessagesQuery defines the optional URL parameters for IndexDirectMessages
@@ -76,12 +97,13 @@ func (c *Client) IndexDirectMessages(ctx context.Context, otherUserID string, re
 	query.Set("other_user_id", otherUserID)
 	if req != nil {
 		if req.BeforeID != "" {
-			query.Add("before_ID", req.BeforeID)
+			query.Add("before_id", req.BeforeID)
 		}
 		if req.SinceID != "" {
 			query.Add("since_id", req.SinceID)
 		}
 	}
+	httpReq.URL.RawQuery = query.Encode()
 
 	var resp IndexDirectMessagesResponse
 	err = c.doWithAuthToken(ctx, httpReq, &resp)
@@ -105,10 +127,23 @@ specify a replacement charmap to substitute emoji characters
 
 The character map is an array of arrays containing rune data
 ([[{pack_id,offset}],...]).
+
+Only image, location, emoji, reply, file and video
+attachments are supported in direct messages.
 */
 func (c *Client) CreateDirectMessage(ctx context.Context, m *Message) (*Message, error) {
+	if err := c.validate(validID("recipient_id", m.RecipientID), m.validate); err != nil {
+		return nil, err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase + createDirectMessageEndpoint)
 
+	for _, attachment := range m.Attachments {
+		if attachment != nil && !directMessageAttachmentTypes[attachment.Type] {
+			return nil, fmt.Errorf("attachments of type %s aren't supported in direct messages", attachment.Type)
+		}
+	}
+
 	m.SourceGUID = uuid.New().String()
 	var data = struct {
 		DirectMessage *Message `json:"direct_message,omitempty"`
@@ -129,6 +164,10 @@ func (c *Client) CreateDirectMessage(ctx context.Context, m *Message) (*Message,
 	var resp struct {
 		*Message `json:"direct_message"`
 	}
+
+	// The SourceGUID lets the server deduplicate, so the request is safe to retry
+	ctx = withIdempotency(ctx)
+	ctx = withRateLimitScope(ctx, RequestClassMessage, "chat:"+m.RecipientID)
 	err = c.doWithAuthToken(ctx, httpReq, &resp)
 	if err != nil {
 		return nil, err
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -46,6 +47,23 @@ func (s *DirectMessagesAPISuite) TestDirectMessagesCreate() {
 	s.Assert().NotZero(*message)
 }
 
+func (s *DirectMessagesAPISuite) TestDirectMessagesCreate_UnsupportedAttachment() {
+	_, err := s.client.CreateDirectMessage(
+		context.Background(),
+		&Message{
+			RecipientID: "123",
+			Text:        "@all",
+			Attachments: []*Attachment{{Type: Mentions, Loci: [][]int{{0, 4}}, UserIDs: []string{"123"}}},
+		},
+	)
+	s.Assert().Error(err)
+}
+
+func TestConversationID(t *testing.T) {
+	assert.Equal(t, "9+10", ConversationID("10", "9"))
+	assert.Equal(t, "9+10", ConversationID("9", "10"))
+}
+
 func TestDirectMessagesAPISuite(t *testing.T) {
 	suite.Run(t, new(DirectMessagesAPISuite))
 }
@@ -57,6 +75,7 @@ func directMessagesTestRouter() *mux.Router {
 	// Index
 	router.Path("/direct_messages").
 		Methods("GET").
+		Queries("other_user_id", "123", "before_id", "0123456789", "since_id", "9876543210").
 		Name("IndexDirectMessages").
 		HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			w.WriteHeader(200)
@@ -0,0 +1,121 @@
+package groupme
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Errors matched by API errors, according to their HTTP status code.
+// Use errors.Is to check the cause of a failed request:
+//
+//	if errors.Is(err, groupme.ErrRateLimited) {
+//		...
+//	}
+var (
+	// 304 Not Modified, e.g. no more messages to page through
+	ErrNotModified = errors.New("groupme: not modified")
+	// 401 Unauthorized
+	ErrUnauthorized = errors.New("groupme: unauthorized")
+	// 404 Not Found
+	ErrNotFound = errors.New("groupme: not found")
+	// 420 Enhance Your Calm or 429 Too Many Requests
+	ErrRateLimited = errors.New("groupme: rate limited")
+	// Any 5XX status
+	ErrServerError = errors.New("groupme: server error")
+)
+
+// Is reports whether the Meta code matches the target error.
+// Satisfies the errors.Is interface
+func (m Meta) Is(target error) bool {
+	switch target {
+	case ErrNotModified:
+		return m.Code == http.StatusNotModified
+	case ErrUnauthorized:
+		return m.Code == http.StatusUnauthorized
+	case ErrNotFound:
+		return m.Code == http.StatusNotFound
+	case ErrRateLimited:
+		return m.Code == StatusEnhanceYourCalm || m.Code == http.StatusTooManyRequests
+	case ErrServerError:
+		return m.Code >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// maxErrorBodyLength is the length the response body is truncated to in an APIError
+const maxErrorBodyLength = 512
+
+// APIError is returned for every response with a status code of 300 or more.
+// It wraps the Meta returned by GroupMe, so errors.As can still be used
+// to retrieve the Meta
+type APIError struct {
+	// HTTP method of the request
+	Method string
+	// Endpoint path and query of the request, with the token redacted
+	Endpoint string
+	// HTTP status code of the response
+	StatusCode int
+	// Start of the response body
+	Body string
+	// Parsed Retry-After header. Zero if absent
+	RetryAfter time.Duration
+	// Meta returned in the response body. If the body had
+	// none, the code is set to the status code
+	Meta Meta
+}
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte, meta Meta) *APIError {
+	if meta.Code == 0 {
+		meta.Code = resp.StatusCode
+	}
+	if len(body) > maxErrorBodyLength {
+		body = body[:maxErrorBodyLength]
+	}
+
+	return &APIError{
+		Method:     req.Method,
+		Endpoint:   redactedEndpoint(req.URL),
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: retryAfter(resp.Header),
+		Meta:       meta,
+	}
+}
+
+// Error returns the request, status and GroupMe errors as a string.
+// Satisfies the error interface
+func (e *APIError) Error() string {
+	description := HTTPStatusText(e.StatusCode)
+	if description == "" {
+		description = http.StatusText(e.StatusCode)
+	}
+
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Endpoint, e.StatusCode, description)
+	if len(e.Meta.Errors) > 0 {
+		msg += fmt.Sprintf(" %v", e.Meta.Errors)
+	}
+	return msg
+}
+
+// Unwrap returns the Meta.
+// Satisfies the errors.Unwrap interface
+func (e *APIError) Unwrap() error {
+	return &e.Meta
+}
+
+// redactedEndpoint returns the URL path and query, without the authorization token
+func redactedEndpoint(u *url.URL) string {
+	query := u.Query()
+	if query.Get("token") != "" {
+		query.Set("token", "REDACTED")
+	}
+
+	endpoint := u.Path
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	return endpoint
+}
@@ -0,0 +1,75 @@
+package groupme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeta_Is(t *testing.T) {
+	assert.True(t, errors.Is(Meta{Code: http.StatusNotModified}, ErrNotModified))
+	assert.True(t, errors.Is(&Meta{Code: http.StatusUnauthorized}, ErrUnauthorized))
+	assert.True(t, errors.Is(Meta{Code: http.StatusNotFound}, ErrNotFound))
+	assert.True(t, errors.Is(Meta{Code: StatusEnhanceYourCalm}, ErrRateLimited))
+	assert.True(t, errors.Is(Meta{Code: http.StatusTooManyRequests}, ErrRateLimited))
+	assert.True(t, errors.Is(Meta{Code: http.StatusBadGateway}, ErrServerError))
+
+	assert.False(t, errors.Is(Meta{Code: http.StatusBadRequest}, ErrServerError))
+	assert.False(t, errors.Is(Meta{Code: http.StatusNotFound}, ErrUnauthorized))
+}
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(StatusEnhanceYourCalm)
+		fmt.Fprint(w, `{"meta": {"code": 420, "errors": ["slow down"]}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("secret")
+	client.apiEndpointBase = server.URL
+
+	_, err := client.ShowGroup(context.Background(), "123")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrRateLimited))
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "GET", apiErr.Method)
+	assert.Equal(t, "/groups/123?token=REDACTED", apiErr.Endpoint)
+	assert.Equal(t, StatusEnhanceYourCalm, apiErr.StatusCode)
+	assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+	assert.Contains(t, apiErr.Body, "slow down")
+	assert.NotContains(t, apiErr.Error(), "secret")
+
+	var meta *Meta
+	require.True(t, errors.As(err, &meta))
+	assert.Equal(t, []string{"slow down"}, meta.Errors)
+}
+
+func TestAPIError_NoMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "<html>Bad Gateway</html>")
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	client.apiEndpointBase = server.URL
+
+	err := client.DestroyGroup(context.Background(), "123")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrServerError))
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.Meta.Code)
+	assert.Equal(t, "<html>Bad Gateway</html>", apiErr.Body)
+}
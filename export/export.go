@@ -0,0 +1,238 @@
+// Package export archives a group's message history as a JSONL file of
+// lossless Message records, a self-contained static HTML transcript and
+// a plain-text log.
+//
+//	exporter := export.New(client, export.WithImages(nil))
+//	result, err := exporter.Export(ctx, groupID, "archive")
+//
+// Exporting to a directory holding an earlier export only fetches the
+// messages newer than it, appending them to the JSONL file and log, and
+// regenerating the HTML transcript.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/densestvoid/groupme"
+)
+
+// Output file names, within the export directory
+const (
+	JSONLFile  = "messages.jsonl"
+	HTMLFile   = "transcript.html"
+	TextFile   = "transcript.txt"
+	ImagesDir  = "images"
+	groupFile  = "group.json"
+	filePerm   = 0644
+	dirPerm    = 0755
+	bufferSize = 64 << 10
+)
+
+// Format is an export file format
+type Format string
+
+// Export formats
+const (
+	FormatJSONL Format = "jsonl"
+	FormatHTML  Format = "html"
+	FormatText  Format = "text"
+)
+
+// ParseFormat returns the Format named s
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatJSONL, FormatHTML, FormatText:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown export format %q", s)
+}
+
+// Exporter exports groups through a Client
+type Exporter struct {
+	client      *groupme.Client
+	formats     map[Format]bool
+	images      bool
+	imageClient *http.Client
+}
+
+// Option configures an Exporter
+type Option func(*Exporter)
+
+// WithFormats sets the formats written besides JSONL, which is always
+// written as incremental exports start from it. Defaults to every format
+func WithFormats(formats ...Format) Option {
+	return func(e *Exporter) {
+		e.formats = map[Format]bool{FormatJSONL: true}
+		for _, f := range formats {
+			e.formats[f] = true
+		}
+	}
+}
+
+// WithImages downloads the pictures of image attachments into the images
+// directory, linking the HTML transcript to them. The client defaults to
+// http.DefaultClient
+func WithImages(client *http.Client) Option {
+	return func(e *Exporter) {
+		e.images = true
+		e.imageClient = client
+		if e.imageClient == nil {
+			e.imageClient = http.DefaultClient
+		}
+	}
+}
+
+// New creates an Exporter
+func New(client *groupme.Client, options ...Option) *Exporter {
+	e := &Exporter{
+		client:  client,
+		formats: map[Format]bool{FormatJSONL: true, FormatHTML: true, FormatText: true},
+	}
+
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
+}
+
+// Result summarizes an export
+type Result struct {
+	Group *groupme.Group
+	// New is the number of messages exported, Total the number in the export
+	New, Total int
+}
+
+// Export writes the group's history to the directory, creating it if needed.
+// If the directory holds an earlier export, only newer messages are fetched
+func (e *Exporter) Export(ctx context.Context, groupID, dir string) (*Result, error) {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, err
+	}
+
+	group, err := e.client.ShowGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeJSON(filepath.Join(dir, groupFile), group); err != nil {
+		return nil, err
+	}
+
+	existing, err := ReadJSONL(filepath.Join(dir, JSONLFile))
+	if err != nil {
+		return nil, err
+	}
+
+	options := &groupme.MessageIteratorOptions{Direction: groupme.IterateForward}
+	if len(existing) > 0 {
+		options.StartID = existing[len(existing)-1].ID
+	}
+
+	var fetched []*groupme.Message
+	iter := e.client.IterateMessages(groupID, options)
+	for iter.Next(ctx) {
+		fetched = append(fetched, iter.Message())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	if e.images {
+		for _, msg := range fetched {
+			if err := e.downloadImages(ctx, dir, msg); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := appendJSONL(filepath.Join(dir, JSONLFile), fetched); err != nil {
+		return nil, err
+	}
+
+	all := append(existing, fetched...)
+	t := newTranscript(group, dir, e.images)
+	if e.formats[FormatText] {
+		// Only append to a log written by an earlier export
+		messages := fetched
+		if _, err := os.Stat(filepath.Join(dir, TextFile)); os.IsNotExist(err) {
+			messages = all
+		}
+		if err := t.appendText(filepath.Join(dir, TextFile), messages); err != nil {
+			return nil, err
+		}
+	}
+	if e.formats[FormatHTML] {
+		if err := t.writeHTML(filepath.Join(dir, HTMLFile), all); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{Group: group, New: len(fetched), Total: len(all)}, nil
+}
+
+/*//////// JSONL ////////*/
+
+// ReadJSONL reads the messages of a JSONL export, oldest first.
+// A missing file has no messages
+func ReadJSONL(path string) ([]*groupme.Message, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var messages []*groupme.Message
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, bufferSize), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var msg groupme.Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+		messages = append(messages, &msg)
+	}
+	return messages, scanner.Err()
+}
+
+func appendJSONL(path string, messages []*groupme.Message) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePerm)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriterSize(f, bufferSize)
+	encoder := json.NewEncoder(w)
+	for _, msg := range messages {
+		if err := encoder.Encode(msg); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, filePerm)
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/densestvoid/groupme"
+	"github.com/densestvoid/groupme/groupmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	server := groupmetest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	alice := groupme.NewClient(server.AddUser(groupme.User{ID: "1", Name: "Alice"}), server.ClientOptions()...)
+	bob := groupme.NewClient(server.AddUser(groupme.User{ID: "2", Name: "Bob"}), server.ClientOptions()...)
+	group := server.AddGroup("1", groupme.GroupSettings{Name: "Archive <Team>"}, "2")
+
+	picture, err := alice.UploadPicture(ctx, image.NewRGBA(image.Rect(0, 0, 1, 1)), groupme.PictureEncodingPNG)
+	require.NoError(t, err)
+
+	server.AddMessage(group.ID, "2", "First")
+	msg, err := groupme.NewMessageBuilder(server.Group(group.ID)).Text("Look <here> ").MentionNickname("Bob").Build()
+	require.NoError(t, err)
+	msg.Attachments = append(msg.Attachments, &groupme.Attachment{Type: groupme.Image, URL: picture.Base})
+	sent, err := alice.CreateMessage(ctx, group.ID, msg)
+	require.NoError(t, err)
+	require.NoError(t, bob.CreateLike(ctx, group.ID, sent.ID))
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	exporter := New(alice, WithImages(server.HTTPClient()))
+
+	result, err := exporter.Export(ctx, group.ID, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.New)
+	assert.Equal(t, 2, result.Total)
+
+	messages, err := ReadJSONL(filepath.Join(dir, JSONLFile))
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "First", messages[0].Text)
+	assert.Equal(t, []string{"2"}, messages[1].MentionedUserIDs())
+
+	images, err := os.ReadDir(filepath.Join(dir, ImagesDir))
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+
+	page, err := os.ReadFile(filepath.Join(dir, HTMLFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<title>Archive &lt;Team&gt;</title>")
+	assert.Contains(t, string(page), `Look &lt;here&gt; <span class="mention" title="Bob">@Bob</span>`)
+	assert.Contains(t, string(page), `src="images/`+images[0].Name()+`"`)
+	assert.Contains(t, string(page), "&hearts; 1")
+
+	// Re-exporting appends only new messages
+	server.AddMessage(group.ID, "2", "Later")
+	result, err = exporter.Export(ctx, group.ID, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.New)
+	assert.Equal(t, 3, result.Total)
+
+	messages, err = ReadJSONL(filepath.Join(dir, JSONLFile))
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.Equal(t, "Later", messages[2].Text)
+
+	log, err := os.ReadFile(filepath.Join(dir, TextFile))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(log)), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[0], "Bob: First"))
+	assert.Contains(t, lines[1], "Alice: Look <here> @Bob [image: "+picture.Base+"] (1 likes)")
+	assert.True(t, strings.HasSuffix(lines[2], "Bob: Later"))
+
+	page, err = os.ReadFile(filepath.Join(dir, HTMLFile))
+	require.NoError(t, err)
+	assert.True(t, bytes.Contains(page, []byte("3 messages")))
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+
+	"github.com/densestvoid/groupme"
+)
+
+// Treated as a constant
+var unsafeFileNameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// imageFileName returns the name a downloaded picture is saved as, from its URL
+func imageFileName(pictureURL string) string {
+	u, err := url.Parse(pictureURL)
+	if err != nil {
+		return ""
+	}
+
+	name := unsafeFileNameRegex.ReplaceAllString(path.Base(u.Path), "_")
+	if name == "" || name == "." || name == "_" {
+		return ""
+	}
+	return name
+}
+
+func (e *Exporter) downloadImages(ctx context.Context, dir string, msg *groupme.Message) error {
+	for _, pictureURL := range msg.PictureURLs() {
+		name := imageFileName(pictureURL)
+		if name == "" {
+			continue
+		}
+
+		dst := filepath.Join(dir, ImagesDir, name)
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := e.download(ctx, pictureURL, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// download saves the URL's content to dst, only creating dst once the download completes
+func (e *Exporter) download(ctx context.Context, src, dst string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", src, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.imageClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", src, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), dirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
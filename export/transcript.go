@@ -0,0 +1,232 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/densestvoid/groupme"
+)
+
+// timeLayout formats message times, in UTC
+const timeLayout = "2006-01-02 15:04:05"
+
+// transcript renders messages, resolving senders through the group's members
+type transcript struct {
+	group   *groupme.Group
+	dir     string
+	images  bool
+	members map[string]*groupme.Member
+}
+
+func newTranscript(group *groupme.Group, dir string, images bool) *transcript {
+	t := &transcript{
+		group:   group,
+		dir:     dir,
+		images:  images,
+		members: map[string]*groupme.Member{},
+	}
+	for _, member := range group.Members {
+		t.members[member.UserID] = member
+	}
+	return t
+}
+
+// name returns the sender's current nickname, falling back to the name sent with the message
+func (t *transcript) name(msg *groupme.Message) string {
+	if member, ok := t.members[msg.UserID]; ok && member.Nickname != "" {
+		return member.Nickname
+	}
+	if msg.Name != "" {
+		return msg.Name
+	}
+	return msg.UserID
+}
+
+func (t *transcript) avatar(msg *groupme.Message) string {
+	if member, ok := t.members[msg.UserID]; ok && member.ImageURL != "" {
+		return member.ImageURL
+	}
+	return msg.AvatarURL
+}
+
+// likers returns the names of the users who liked the message
+func (t *transcript) likers(msg *groupme.Message) []string {
+	var names []string
+	for _, userID := range msg.FavoritedBy {
+		if member, ok := t.members[userID]; ok {
+			names = append(names, member.Nickname)
+		} else {
+			names = append(names, userID)
+		}
+	}
+	return names
+}
+
+// imageSrc returns the downloaded picture's relative path, or its URL if it wasn't downloaded
+func (t *transcript) imageSrc(pictureURL string) string {
+	if t.images {
+		if name := imageFileName(pictureURL); name != "" {
+			if _, err := os.Stat(filepath.Join(t.dir, ImagesDir, name)); err == nil {
+				return ImagesDir + "/" + name
+			}
+		}
+	}
+	return pictureURL
+}
+
+/*//////// Text ////////*/
+
+// textLine formats the message as a line of the plain-text log
+func (t *transcript) textLine(msg *groupme.Message) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] ", msg.CreatedAt.ToTime().Format(timeLayout))
+	if msg.System || msg.SenderType == groupme.SenderTypeSystem {
+		b.WriteString("* ")
+	} else {
+		b.WriteString(t.name(msg) + ": ")
+	}
+	b.WriteString(strings.ReplaceAll(msg.Text, "\n", "\n    "))
+
+	for _, pictureURL := range msg.PictureURLs() {
+		b.WriteString(" [image: " + pictureURL + "]")
+	}
+	if len(msg.FavoritedBy) > 0 {
+		fmt.Fprintf(&b, " (%d likes)", len(msg.FavoritedBy))
+	}
+	return b.String()
+}
+
+func (t *transcript) appendText(path string, messages []*groupme.Message) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePerm)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriterSize(f, bufferSize)
+	for _, msg := range messages {
+		if _, err := fmt.Fprintln(w, t.textLine(msg)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+/*//////// HTML ////////*/
+
+// renderText escapes the message text, wrapping mentions in spans
+func (t *transcript) renderText(msg *groupme.Message) template.HTML {
+	return template.HTML(msg.RenderMentions(func(span groupme.MentionSpan) string {
+		return fmt.Sprintf(`<span class="mention" title="%s">%s</span>`,
+			html.EscapeString(t.mentionTitle(span.UserID)), html.EscapeString(span.Text))
+	}, html.EscapeString))
+}
+
+func (t *transcript) mentionTitle(userID string) string {
+	if member, ok := t.members[userID]; ok {
+		return member.Nickname
+	}
+	return userID
+}
+
+type htmlMessage struct {
+	ID     string
+	Time   string
+	Name   string
+	Avatar string
+	System bool
+	Text   template.HTML
+	Images []string
+	Likers []string
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Group.Name}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 48em; margin: 2em auto; color: #222; }
+h1 { margin-bottom: 0; }
+.description { color: #666; }
+.message { display: flex; gap: 0.75em; padding: 0.5em 0; border-bottom: 1px solid #eee; }
+.avatar { width: 40px; height: 40px; border-radius: 50%; flex: none; background: #ddd; }
+.name { font-weight: bold; }
+.time { color: #999; font-size: 0.8em; margin-left: 0.5em; }
+.text { white-space: pre-wrap; overflow-wrap: anywhere; }
+.mention { color: #00aff0; font-weight: bold; }
+.system { color: #666; font-style: italic; }
+.picture { display: block; max-width: 100%; max-height: 30em; margin-top: 0.5em; }
+.likes { color: #e0245e; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>{{.Group.Name}}</h1>
+{{with .Group.Description}}<p class="description">{{.}}</p>{{end}}
+<p class="description">{{len .Messages}} messages</p>
+{{range .Messages}}
+<div class="message{{if .System}} system{{end}}" id="m{{.ID}}">
+{{if .Avatar}}<img class="avatar" src="{{.Avatar}}" alt="">{{else}}<div class="avatar"></div>{{end}}
+<div>
+<div><span class="name">{{.Name}}</span><span class="time">{{.Time}}</span></div>
+<div class="text">{{.Text}}</div>
+{{range .Images}}<a href="{{.}}"><img class="picture" src="{{.}}" alt="picture"></a>{{end}}
+{{with .Likers}}<div class="likes" title="{{range $i, $name := .}}{{if $i}}, {{end}}{{$name}}{{end}}">&hearts; {{len .}}</div>{{end}}
+</div>
+</div>
+{{end}}
+</body>
+</html>
+`))
+
+func (t *transcript) writeHTML(path string, messages []*groupme.Message) error {
+	data := struct {
+		Group    *groupme.Group
+		Messages []htmlMessage
+	}{Group: t.group}
+
+	for _, msg := range messages {
+		m := htmlMessage{
+			ID:     msg.ID,
+			Time:   msg.CreatedAt.ToTime().Format(timeLayout),
+			Name:   t.name(msg),
+			Avatar: t.avatar(msg),
+			System: msg.System || msg.SenderType == groupme.SenderTypeSystem,
+			Text:   t.renderText(msg),
+			Likers: t.likers(msg),
+		}
+		for _, pictureURL := range msg.PictureURLs() {
+			m.Images = append(m.Images, t.imageSrc(pictureURL))
+		}
+		data.Messages = append(data.Messages, m)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriterSize(tmp, bufferSize)
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
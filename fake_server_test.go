@@ -0,0 +1,52 @@
+package groupme_test
+
+// The tests against the groupmetest fake server are in the external
+// groupme_test package, as groupmetest imports groupme
+
+import (
+	"testing"
+
+	"github.com/densestvoid/groupme"
+	"github.com/densestvoid/groupme/groupmetest"
+)
+
+// testUsers are the users of the fake server started by newTeam
+var testUsers = []groupme.User{
+	{ID: "1", Name: "Alice", ImageURL: "https://i.groupme.com/alice"},
+	{ID: "2", Name: "Bob"},
+	{ID: "3", Name: "Carol", Email: "carol@example.com"},
+}
+
+// team is a fake server with the testUsers, where Alice owns the group "Team"
+type team struct {
+	server *groupmetest.Server
+	group  *groupme.Group
+	// Alice's client
+	alice  *groupme.Client
+	tokens map[string]string
+}
+
+// newTeam starts a fake server with the testUsers, where Alice owns the group
+// "Team" with the users of memberIDs as its other members. The server is
+// closed when the test ends
+func newTeam(t *testing.T, memberIDs ...string) *team {
+	t.Helper()
+	server := groupmetest.NewServer()
+	t.Cleanup(server.Close)
+
+	tm := &team{server: server, tokens: map[string]string{}}
+	for _, user := range testUsers {
+		tm.tokens[user.ID] = server.AddUser(user)
+	}
+	tm.group = server.AddGroup("1", groupme.GroupSettings{Name: "Team"}, memberIDs...)
+	tm.alice = tm.client(t, "1")
+	return tm
+}
+
+// client returns a client of the user, closed when the test ends
+func (tm *team) client(t *testing.T, userID string) *groupme.Client {
+	t.Helper()
+	client := groupme.NewClient(tm.tokens[userID], tm.server.ClientOptions()...)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
@@ -0,0 +1,95 @@
+package groupmetest
+
+import (
+	"net/http"
+
+	"github.com/densestvoid/groupme"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#blocks
+
+// blockQuery returns the user and other user query parameters. The user
+// must be the authenticated user. Otherwise it writes the error response
+func blockQuery(w http.ResponseWriter, req *http.Request, u *user) (userID, otherUserID string, ok bool) {
+	query := req.URL.Query()
+	userID = query.Get("user")
+	otherUserID = query.Get("otherUser")
+
+	if userID != u.ID {
+		writeError(w, http.StatusForbidden, "user must be the authenticated user")
+		return "", "", false
+	}
+	return userID, otherUserID, true
+}
+
+func (s *Server) findBlock(userID, otherUserID string) int {
+	for i, block := range s.blocks {
+		if block.UserID == userID && block.BlockedUserID == otherUserID {
+			return i
+		}
+	}
+	return -1
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) indexBlocks(w http.ResponseWriter, req *http.Request, u *user) {
+	if req.URL.Query().Get("user") != u.ID {
+		writeError(w, http.StatusForbidden, "user must be the authenticated user")
+		return
+	}
+
+	blocks := []*groupme.Block{}
+	for _, block := range s.blocks {
+		if block.UserID == u.ID {
+			blocks = append(blocks, block)
+		}
+	}
+	writeResponse(w, http.StatusOK, map[string]interface{}{"blocks": blocks})
+}
+
+func (s *Server) blockBetween(w http.ResponseWriter, req *http.Request, u *user) {
+	userID, otherUserID, ok := blockQuery(w, req, u)
+	if !ok {
+		return
+	}
+
+	writeResponse(w, http.StatusOK, map[string]bool{"between": s.blocked(userID, otherUserID)})
+}
+
+func (s *Server) createBlock(w http.ResponseWriter, req *http.Request, u *user) {
+	userID, otherUserID, ok := blockQuery(w, req, u)
+	if !ok {
+		return
+	}
+	if _, exists := s.users[otherUserID]; !exists {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	var block *groupme.Block
+	if i := s.findBlock(userID, otherUserID); i >= 0 {
+		block = s.blocks[i]
+	} else {
+		block = &groupme.Block{
+			UserID:        userID,
+			BlockedUserID: otherUserID,
+			CreatedAT:     s.now(),
+		}
+		s.blocks = append(s.blocks, block)
+	}
+
+	writeResponse(w, http.StatusCreated, map[string]interface{}{"block": block})
+}
+
+func (s *Server) unblock(w http.ResponseWriter, req *http.Request, u *user) {
+	userID, otherUserID, ok := blockQuery(w, req, u)
+	if !ok {
+		return
+	}
+
+	if i := s.findBlock(userID, otherUserID); i >= 0 {
+		s.blocks = append(s.blocks[:i], s.blocks[i+1:]...)
+	}
+	writeResponse(w, http.StatusOK, nil)
+}
@@ -0,0 +1,114 @@
+package groupmetest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/densestvoid/groupme"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#bots
+
+/*//////// Handlers ////////*/
+
+func (s *Server) createBot(w http.ResponseWriter, req *http.Request, u *user) {
+	var data struct {
+		Bot *groupme.Bot `json:"bot"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+	if data.Bot == nil || data.Bot.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	g, ok := s.groups[data.Bot.GroupID]
+	if !ok || g.member(u.ID) == nil {
+		writeError(w, http.StatusBadRequest, "invalid group_id")
+		return
+	}
+
+	b := &bot{Bot: *data.Bot, ownerID: u.ID}
+	b.BotID = s.newID()
+	s.bots[b.BotID] = b
+
+	writeResponse(w, http.StatusCreated, b.Bot)
+}
+
+func (s *Server) indexBots(w http.ResponseWriter, req *http.Request, u *user) {
+	resp := []groupme.Bot{}
+	for _, b := range s.bots {
+		if b.ownerID == u.ID {
+			resp = append(resp, b.Bot)
+		}
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return groupme.CompareIDs(resp[i].BotID, resp[j].BotID) < 0
+	})
+
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) destroyBot(w http.ResponseWriter, req *http.Request, u *user) {
+	var data struct {
+		BotID string `json:"bot_id"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+
+	b, ok := s.bots[data.BotID]
+	if !ok || b.ownerID != u.ID {
+		writeError(w, http.StatusNotFound, "bot not found")
+		return
+	}
+
+	delete(s.bots, b.BotID)
+	writeResponse(w, http.StatusOK, nil)
+}
+
+// postBotMessage requires no token, only the bot ID
+func (s *Server) postBotMessage(w http.ResponseWriter, req *http.Request) {
+	var data struct {
+		BotID       string                `json:"bot_id"`
+		Text        string                `json:"text"`
+		PictureURL  string                `json:"picture_url"`
+		Attachments []*groupme.Attachment `json:"attachments"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+
+	b, ok := s.bots[data.BotID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "bot not found")
+		return
+	}
+	g, ok := s.groups[b.GroupID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+
+	m := &groupme.Message{Text: data.Text, ImageURL: data.PictureURL, Attachments: data.Attachments}
+	if !validMessage(w, m) {
+		return
+	}
+
+	s.addGroupMessage(g, &groupme.Message{
+		ID:          s.newID(),
+		CreatedAt:   s.now(),
+		GroupID:     g.ID,
+		BotID:       b.BotID,
+		SenderID:    b.BotID,
+		SenderType:  groupme.SenderTypeBot,
+		Name:        b.Name,
+		AvatarURL:   b.AvatarURL,
+		Text:        m.Text,
+		ImageURL:    m.ImageURL,
+		FavoritedBy: []string{},
+		Attachments: m.Attachments,
+	})
+	w.WriteHeader(http.StatusAccepted)
+}
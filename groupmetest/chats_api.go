@@ -0,0 +1,41 @@
+package groupmetest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/densestvoid/groupme"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#chats
+
+/*//////// Handlers ////////*/
+
+func (s *Server) indexChats(w http.ResponseWriter, req *http.Request, u *user) {
+	var chats []*chat
+	for _, c := range s.chats {
+		if c.userIDs[0] == u.ID || c.userIDs[1] == u.ID {
+			chats = append(chats, c)
+		}
+	}
+	sort.Slice(chats, func(i, j int) bool {
+		if chats[i].updatedAt != chats[j].updatedAt {
+			return chats[i].updatedAt > chats[j].updatedAt
+		}
+		return chats[i].conversationID > chats[j].conversationID
+	})
+
+	start, end := paginate(len(chats), queryInt(req, "page", 1), queryInt(req, "per_page", 20))
+
+	resp := []*groupme.Chat{}
+	for _, c := range chats[start:end] {
+		resp = append(resp, &groupme.Chat{
+			CreatedAt:     c.createdAt,
+			UpdatedAt:     c.updatedAt,
+			LastMessage:   c.messages[len(c.messages)-1],
+			MessagesCount: len(c.messages),
+			OtherUser:     s.users[c.otherUserID(u.ID)].User,
+		})
+	}
+	writeResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,131 @@
+package groupmetest
+
+import (
+	"net/http"
+
+	"github.com/densestvoid/groupme"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#direct_messages
+
+const directMessagesLimit = 20
+
+func (s *Server) blocked(userID, otherUserID string) bool {
+	for _, block := range s.blocks {
+		if (block.UserID == userID && block.BlockedUserID == otherUserID) ||
+			(block.UserID == otherUserID && block.BlockedUserID == userID) {
+			return true
+		}
+	}
+	return false
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) indexDirectMessages(w http.ResponseWriter, req *http.Request, u *user) {
+	query := req.URL.Query()
+	otherUserID := query.Get("other_user_id")
+	if otherUserID == "" {
+		writeError(w, http.StatusBadRequest, "other_user_id is required")
+		return
+	}
+
+	var messages []*groupme.Message
+	if c, ok := s.chats[groupme.ConversationID(u.ID, otherUserID)]; ok {
+		messages = c.messages
+	}
+
+	// DMs are always returned newest first, 20 at a time
+	beforeID := query.Get("before_id")
+	sinceID := query.Get("since_id")
+	page := []*groupme.Message{}
+	for i := len(messages) - 1; i >= 0 && len(page) < directMessagesLimit; i-- {
+		msg := messages[i]
+		if beforeID != "" && groupme.CompareIDs(msg.ID, beforeID) >= 0 {
+			continue
+		}
+		if sinceID != "" && groupme.CompareIDs(msg.ID, sinceID) <= 0 {
+			break
+		}
+		page = append(page, msg)
+	}
+
+	if len(page) == 0 {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, groupme.IndexDirectMessagesResponse{
+		Count:    len(messages),
+		Messages: page,
+	})
+}
+
+func (s *Server) createDirectMessage(w http.ResponseWriter, req *http.Request, u *user) {
+	var data struct {
+		DirectMessage *groupme.Message `json:"direct_message"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+	if data.DirectMessage == nil {
+		data.DirectMessage = &groupme.Message{}
+	}
+	m := data.DirectMessage
+	if !validMessage(w, m) {
+		return
+	}
+
+	for _, attachment := range m.Attachments {
+		if attachment != nil && attachment.Type == groupme.Mentions {
+			writeError(w, http.StatusBadRequest, "mentions aren't supported in direct messages")
+			return
+		}
+	}
+
+	recipient, ok := s.users[m.RecipientID]
+	if !ok || recipient.ID == u.ID {
+		writeError(w, http.StatusBadRequest, "invalid recipient_id")
+		return
+	}
+	if s.blocked(u.ID, recipient.ID) {
+		writeError(w, http.StatusForbidden, "a block exists between the users")
+		return
+	}
+
+	conversationID := groupme.ConversationID(u.ID, recipient.ID)
+	c, ok := s.chats[conversationID]
+	if !ok {
+		c = &chat{
+			conversationID: conversationID,
+			userIDs:        [2]string{u.ID, recipient.ID},
+			createdAt:      s.now(),
+		}
+		s.chats[conversationID] = c
+	}
+
+	msg := findBySourceGUID(c.messages, m.SourceGUID)
+	if msg == nil {
+		msg = &groupme.Message{
+			ID:             s.newID(),
+			SourceGUID:     m.SourceGUID,
+			CreatedAt:      s.now(),
+			UserID:         u.ID,
+			SenderID:       u.ID,
+			SenderType:     groupme.SenderTypeUser,
+			Name:           u.Name,
+			AvatarURL:      u.ImageURL,
+			RecipientID:    recipient.ID,
+			ConversationID: conversationID,
+			Text:           m.Text,
+			ImageURL:       m.ImageURL,
+			FavoritedBy:    []string{},
+			Attachments:    m.Attachments,
+		}
+		c.messages = append(c.messages, msg)
+		c.updatedAt = msg.CreatedAt
+		s.publishDirectMessage(c, msg)
+	}
+
+	writeResponse(w, http.StatusCreated, map[string]interface{}{"direct_message": msg})
+}
@@ -0,0 +1,278 @@
+package groupmetest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/densestvoid/groupme"
+	"github.com/gorilla/mux"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#groups
+
+/*//////// Helpers ////////*/
+
+func (s *Server) newGroup(creator *user, settings groupme.GroupSettings) *group {
+	g := &group{
+		Group: groupme.Group{
+			ID:            s.newID(),
+			Type:          "private",
+			CreatorUserID: creator.ID,
+			CreatedAt:     s.now(),
+		},
+		former: map[string]bool{},
+	}
+	s.applyGroupSettings(g, settings)
+	g.members = append(g.members, s.newMember(creator, ""))
+	s.groups[g.ID] = g
+
+	return g
+}
+
+func (s *Server) applyGroupSettings(g *group, settings groupme.GroupSettings) {
+	g.Name = settings.Name
+	g.Description = settings.Description
+	g.ImageURL = settings.ImageURL
+	g.UpdatedAt = s.now()
+
+	if settings.Share {
+		if g.shareToken == "" {
+			g.shareToken = fmt.Sprintf("share%s", s.newID())
+		}
+		g.ShareURL = fmt.Sprintf("%s/join_group/%s/%s", s.URL, g.ID, g.shareToken)
+	} else {
+		g.shareToken = ""
+		g.ShareURL = ""
+	}
+}
+
+func (s *Server) newMember(u *user, guid string) *groupme.Member {
+	if guid == "" {
+		guid = fmt.Sprintf("guid%s", s.newID())
+	}
+
+	return &groupme.Member{
+		ID:           s.newID(),
+		UserID:       u.ID,
+		Nickname:     u.Name,
+		ImageURL:     u.ImageURL,
+		AppInstalled: true,
+		GUID:         guid,
+	}
+}
+
+// groupResponse copies the group, filling in its members and message summary
+func (s *Server) groupResponse(g *group, omitMembers bool) *groupme.Group {
+	var resp groupme.Group
+	clone(&g.Group, &resp)
+
+	if !omitMembers {
+		clone(g.members, &resp.Members)
+	}
+
+	resp.Messages = groupme.GroupMessages{Count: uint(len(g.messages))}
+	if len(g.messages) > 0 {
+		last := g.messages[len(g.messages)-1]
+		resp.Messages.LastMessageID = last.ID
+		resp.Messages.LastMessageCreatedAt = last.CreatedAt
+		resp.Messages.Preview = groupme.MessagePreview{
+			Nickname: last.Name,
+			Text:     last.Text,
+			ImageURL: last.ImageURL,
+		}
+		clone(last.Attachments, &resp.Messages.Preview.Attachments)
+	}
+
+	return &resp
+}
+
+// memberGroup returns the group from the request path if the user is a member.
+// Otherwise it writes the error response and returns nil
+func (s *Server) memberGroup(w http.ResponseWriter, req *http.Request, u *user) *group {
+	g, ok := s.groups[mux.Vars(req)["group_id"]]
+	if !ok || g.member(u.ID) == nil {
+		writeError(w, http.StatusNotFound, "group not found")
+		return nil
+	}
+	return g
+}
+
+// sortedGroups returns the groups matching the filter, most recently updated first
+func (s *Server) sortedGroups(filter func(*group) bool) []*group {
+	var groups []*group
+	for _, g := range s.groups {
+		if filter(g) {
+			groups = append(groups, g)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].UpdatedAt != groups[j].UpdatedAt {
+			return groups[i].UpdatedAt > groups[j].UpdatedAt
+		}
+		return groupme.CompareIDs(groups[i].ID, groups[j].ID) > 0
+	})
+	return groups
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) indexGroups(w http.ResponseWriter, req *http.Request, u *user) {
+	groups := s.sortedGroups(func(g *group) bool {
+		return g.member(u.ID) != nil
+	})
+
+	start, end := paginate(len(groups), queryInt(req, "page", 1), queryInt(req, "per_page", 10))
+	omitMembers := strings.Contains(req.URL.Query().Get("omit"), "memberships")
+
+	resp := []*groupme.Group{}
+	for _, g := range groups[start:end] {
+		resp = append(resp, s.groupResponse(g, omitMembers))
+	}
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) formerGroups(w http.ResponseWriter, req *http.Request, u *user) {
+	groups := s.sortedGroups(func(g *group) bool {
+		return g.former[u.ID]
+	})
+
+	resp := []*groupme.Group{}
+	for _, g := range groups {
+		resp = append(resp, s.groupResponse(g, false))
+	}
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) showGroup(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+	writeResponse(w, http.StatusOK, s.groupResponse(g, false))
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, req *http.Request, u *user) {
+	var settings groupme.GroupSettings
+	if !decodeBody(w, req, &settings) {
+		return
+	}
+	if settings.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	g := s.newGroup(u, settings)
+	writeResponse(w, http.StatusCreated, s.groupResponse(g, false))
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	var settings groupme.GroupSettings
+	if !decodeBody(w, req, &settings) {
+		return
+	}
+	if settings.Name == "" {
+		settings.Name = g.Name
+	}
+
+	s.applyGroupSettings(g, settings)
+	writeResponse(w, http.StatusOK, s.groupResponse(g, false))
+}
+
+func (s *Server) destroyGroup(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+	if g.CreatorUserID != u.ID {
+		writeError(w, http.StatusForbidden, "only the creator can destroy the group")
+		return
+	}
+
+	delete(s.groups, g.ID)
+	for id, b := range s.bots {
+		if b.GroupID == g.ID {
+			delete(s.bots, id)
+		}
+	}
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func (s *Server) joinGroup(w http.ResponseWriter, req *http.Request, u *user) {
+	vars := mux.Vars(req)
+	g, ok := s.groups[vars["group_id"]]
+	if !ok || g.shareToken == "" || g.shareToken != vars["share_token"] {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+
+	if g.member(u.ID) == nil {
+		g.members = append(g.members, s.newMember(u, ""))
+		delete(g.former, u.ID)
+		s.announce(g, "%s has joined the group", u.Name)
+	}
+	writeResponse(w, http.StatusOK, s.groupResponse(g, false))
+}
+
+func (s *Server) rejoinGroup(w http.ResponseWriter, req *http.Request, u *user) {
+	var data struct {
+		GroupID string `json:"group_id"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+
+	g, ok := s.groups[data.GroupID]
+	if !ok || !g.former[u.ID] {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+
+	g.members = append(g.members, s.newMember(u, ""))
+	delete(g.former, u.ID)
+	s.announce(g, "%s has rejoined the group", u.Name)
+	writeResponse(w, http.StatusOK, s.groupResponse(g, false))
+}
+
+func (s *Server) changeGroupOwner(w http.ResponseWriter, req *http.Request, u *user) {
+	var data struct {
+		Requests []groupme.ChangeOwnerRequest `json:"requests"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+
+	results := []groupme.ChangeOwnerResult{}
+	for _, request := range data.Requests {
+		result := groupme.ChangeOwnerResult{
+			GroupID: request.GroupID,
+			OwnerID: request.OwnerID,
+		}
+
+		g, ok := s.groups[request.GroupID]
+		switch {
+		case request.GroupID == "" || request.OwnerID == "":
+			result.Status = groupme.ChangeOwnerBadRequest
+		case !ok || g.member(request.OwnerID) == nil:
+			result.Status = groupme.ChangeOwnerBadGroupOrOwner
+		case g.CreatorUserID != u.ID:
+			result.Status = groupme.ChangeOwnerNotOwner
+		case request.OwnerID == u.ID:
+			result.Status = groupme.ChangeOwnerRequesterNewOwner
+		default:
+			g.CreatorUserID = request.OwnerID
+			g.UpdatedAt = s.now()
+			result.Status = groupme.ChangeOwnerOk
+		}
+
+		results = append(results, result)
+	}
+
+	writeResponse(w, http.StatusOK, map[string]interface{}{"results": results})
+}
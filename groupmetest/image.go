@@ -0,0 +1,59 @@
+package groupmetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/densestvoid/groupme"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) pictureURL(id string) string {
+	return fmt.Sprintf("%s/pictures/%s", s.URL, id)
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) uploadPicture(w http.ResponseWriter, req *http.Request, u *user) {
+	contentType := req.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		writeError(w, http.StatusUnsupportedMediaType, "unsupported Content-Type: "+contentType)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, groupme.MaxPictureSize+1))
+	if err != nil || len(data) == 0 || len(data) > groupme.MaxPictureSize {
+		writeError(w, http.StatusBadRequest, "invalid picture")
+		return
+	}
+
+	id := s.newID()
+	s.pictures[id] = &picture{contentType: contentType, data: data}
+
+	// The image service doesn't wrap its response like the API
+	url := s.pictureURL(id)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"payload": map[string]string{
+			"url":         url,
+			"picture_url": url,
+		},
+	})
+}
+
+func (s *Server) showPicture(w http.ResponseWriter, req *http.Request) {
+	// Thumbnail suffixes serve the original picture
+	id := strings.SplitN(mux.Vars(req)["id"], ".", 2)[0]
+
+	p, ok := s.pictures[id]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", p.contentType)
+	_, _ = w.Write(p.data)
+}
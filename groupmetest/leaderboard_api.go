@@ -0,0 +1,87 @@
+package groupmetest
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/densestvoid/groupme"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#leaderboard
+
+func likedBy(msg *groupme.Message, userID string) bool {
+	for _, id := range msg.FavoritedBy {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// newestFirst returns the messages matching the filter, newest first
+func newestFirst(messages []*groupme.Message, filter func(*groupme.Message) bool) []*groupme.Message {
+	resp := []*groupme.Message{}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if filter(messages[i]) {
+			resp = append(resp, messages[i])
+		}
+	}
+	return resp
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) indexLeaderboard(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	var period time.Duration
+	switch req.URL.Query().Get("period") {
+	case groupme.PeriodDay:
+		period = 24 * time.Hour
+	case groupme.PeriodWeek:
+		period = 7 * 24 * time.Hour
+	case groupme.PeriodMonth:
+		period = 30 * 24 * time.Hour
+	default:
+		writeError(w, http.StatusBadRequest, "period must be one of day, week or month")
+		return
+	}
+
+	since := groupme.FromTime(s.Now().Add(-period))
+	messages := newestFirst(g.messages, func(msg *groupme.Message) bool {
+		return msg.CreatedAt >= since && len(msg.FavoritedBy) > 0
+	})
+	sort.SliceStable(messages, func(i, j int) bool {
+		return len(messages[i].FavoritedBy) > len(messages[j].FavoritedBy)
+	})
+
+	writeResponse(w, http.StatusOK, map[string]interface{}{"messages": messages})
+}
+
+func (s *Server) myLikesLeaderboard(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	messages := newestFirst(g.messages, func(msg *groupme.Message) bool {
+		return likedBy(msg, u.ID)
+	})
+	writeResponse(w, http.StatusOK, map[string]interface{}{"messages": messages})
+}
+
+func (s *Server) myHitsLeaderboard(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	messages := newestFirst(g.messages, func(msg *groupme.Message) bool {
+		return msg.UserID == u.ID && len(msg.FavoritedBy) > 0
+	})
+	writeResponse(w, http.StatusOK, map[string]interface{}{"messages": messages})
+}
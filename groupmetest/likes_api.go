@@ -0,0 +1,64 @@
+package groupmetest
+
+import (
+	"net/http"
+
+	"github.com/densestvoid/groupme"
+	"github.com/gorilla/mux"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#likes
+
+// conversationMessage returns the message from the request path, in either a
+// group or direct message conversation the user is part of. Otherwise it
+// writes the error response and returns nil
+func (s *Server) conversationMessage(w http.ResponseWriter, req *http.Request, u *user) *groupme.Message {
+	vars := mux.Vars(req)
+
+	var msg *groupme.Message
+	if g, ok := s.groups[vars["conversation_id"]]; ok && g.member(u.ID) != nil {
+		msg = g.message(vars["message_id"])
+	} else if c, ok := s.chats[vars["conversation_id"]]; ok && (c.userIDs[0] == u.ID || c.userIDs[1] == u.ID) {
+		msg = c.message(vars["message_id"])
+	}
+
+	if msg == nil {
+		writeError(w, http.StatusNotFound, "message not found")
+	}
+	return msg
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) createLike(w http.ResponseWriter, req *http.Request, u *user) {
+	msg := s.conversationMessage(w, req, u)
+	if msg == nil {
+		return
+	}
+
+	for _, userID := range msg.FavoritedBy {
+		if userID == u.ID {
+			writeResponse(w, http.StatusOK, nil)
+			return
+		}
+	}
+
+	msg.FavoritedBy = append(msg.FavoritedBy, u.ID)
+	s.publishLike(msg, u.ID)
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func (s *Server) destroyLike(w http.ResponseWriter, req *http.Request, u *user) {
+	msg := s.conversationMessage(w, req, u)
+	if msg == nil {
+		return
+	}
+
+	for i, userID := range msg.FavoritedBy {
+		if userID == u.ID {
+			msg.FavoritedBy = append(msg.FavoritedBy[:i], msg.FavoritedBy[i+1:]...)
+			break
+		}
+	}
+	writeResponse(w, http.StatusOK, nil)
+}
@@ -0,0 +1,149 @@
+package groupmetest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/densestvoid/groupme"
+	"github.com/gorilla/mux"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#members
+
+// findUser returns the user identified by the member's user ID, phone number or email
+func (s *Server) findUser(m *groupme.Member) *user {
+	if m.UserID != "" {
+		return s.users[m.UserID]
+	}
+
+	for _, u := range s.users {
+		if (m.PhoneNumber != "" && string(u.PhoneNumber) == m.PhoneNumber) ||
+			(m.Email != "" && u.Email == m.Email) {
+			return u
+		}
+	}
+	return nil
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) addMembers(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	var data struct {
+		Members []*groupme.Member `json:"members"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+
+	// Failed adds are omitted from the results
+	results := []*groupme.Member{}
+	var nicknames []string
+	for _, requested := range data.Members {
+		added := s.findUser(requested)
+		if requested.Nickname == "" || added == nil || g.member(added.ID) != nil {
+			continue
+		}
+
+		member := s.newMember(added, requested.GUID)
+		member.Nickname = requested.Nickname
+		g.members = append(g.members, member)
+		delete(g.former, added.ID)
+		s.publishMembership(g, added.ID)
+
+		results = append(results, member)
+		nicknames = append(nicknames, member.Nickname)
+	}
+	if len(nicknames) > 0 {
+		s.announce(g, "%s added %s to the group.", g.member(u.ID).Nickname, strings.Join(nicknames, " and "))
+	}
+
+	resultsID := s.newID()
+	s.addResults[resultsID] = results
+	writeResponse(w, http.StatusAccepted, map[string]string{"results_id": resultsID})
+}
+
+func (s *Server) addMembersResults(w http.ResponseWriter, req *http.Request, u *user) {
+	if g := s.memberGroup(w, req, u); g == nil {
+		return
+	}
+
+	resultsID := mux.Vars(req)["results_id"]
+	results, ok := s.addResults[resultsID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "results not found")
+		return
+	}
+
+	if s.addPolls[resultsID] < s.PendingAddResults {
+		s.addPolls[resultsID]++
+		writeError(w, http.StatusServiceUnavailable, "results are not ready yet")
+		return
+	}
+
+	writeResponse(w, http.StatusOK, map[string]interface{}{"members": results})
+}
+
+func (s *Server) removeMember(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	membershipID := mux.Vars(req)["membership_id"]
+	for i, member := range g.members {
+		if member.ID != membershipID {
+			continue
+		}
+
+		if member.UserID == g.CreatorUserID {
+			writeError(w, http.StatusBadRequest, "the creator cannot be removed")
+			return
+		}
+
+		g.members = append(g.members[:i], g.members[i+1:]...)
+		g.former[member.UserID] = true
+		if member.UserID == u.ID {
+			s.announce(g, "%s has left the group.", member.Nickname)
+		} else {
+			s.announce(g, "%s removed %s from the group.", g.member(u.ID).Nickname, member.Nickname)
+		}
+		writeResponse(w, http.StatusOK, nil)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "membership not found")
+}
+
+func (s *Server) updateMember(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	var data struct {
+		Membership struct {
+			Nickname string `json:"nickname"`
+		} `json:"membership"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+
+	nickname := data.Membership.Nickname
+	if len(nickname) < 1 || len(nickname) > 50 {
+		writeError(w, http.StatusBadRequest, "nickname must be between 1 and 50 characters")
+		return
+	}
+
+	member := g.member(u.ID)
+	if member.Nickname != nickname {
+		s.announce(g, "%s changed name to %s", member.Nickname, nickname)
+	}
+	member.Nickname = nickname
+	writeResponse(w, http.StatusOK, member)
+}
@@ -0,0 +1,220 @@
+package groupmetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/densestvoid/groupme"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#messages
+
+const (
+	defaultMessagesLimit = 20
+	maxMessagesLimit     = 100
+	maxTextLength        = 1000
+)
+
+// pageMessages selects messages, which are ordered oldest first, the way
+// IndexMessages does: before_id and since_id pages are newest first,
+// after_id pages are oldest first
+func pageMessages(messages []*groupme.Message, req *http.Request) []*groupme.Message {
+	query := req.URL.Query()
+	limit := queryInt(req, "limit", defaultMessagesLimit)
+	if limit < 1 || limit > maxMessagesLimit {
+		limit = maxMessagesLimit
+	}
+
+	page := []*groupme.Message{}
+	if afterID := query.Get("after_id"); afterID != "" {
+		for _, msg := range messages {
+			if len(page) == limit {
+				break
+			}
+			if groupme.CompareIDs(msg.ID, afterID) > 0 {
+				page = append(page, msg)
+			}
+		}
+		return page
+	}
+
+	beforeID := query.Get("before_id")
+	sinceID := query.Get("since_id")
+	for i := len(messages) - 1; i >= 0 && len(page) < limit; i-- {
+		msg := messages[i]
+		if beforeID != "" && groupme.CompareIDs(msg.ID, beforeID) >= 0 {
+			continue
+		}
+		if sinceID != "" && groupme.CompareIDs(msg.ID, sinceID) <= 0 {
+			break
+		}
+		page = append(page, msg)
+	}
+	return page
+}
+
+// findBySourceGUID returns the message with the source GUID, so
+// retried requests don't post duplicates
+func findBySourceGUID(messages []*groupme.Message, sourceGUID string) *groupme.Message {
+	if sourceGUID == "" {
+		return nil
+	}
+	for _, msg := range messages {
+		if msg.SourceGUID == sourceGUID {
+			return msg
+		}
+	}
+	return nil
+}
+
+func validMessage(w http.ResponseWriter, m *groupme.Message) bool {
+	if m.Text == "" && len(m.Attachments) == 0 && m.ImageURL == "" {
+		writeError(w, http.StatusBadRequest, "text or attachments are required")
+		return false
+	}
+	if len([]rune(m.Text)) > maxTextLength {
+		writeError(w, http.StatusBadRequest, "text is longer than 1000 characters")
+		return false
+	}
+	return true
+}
+
+// newGroupMessage adds a message sent by the user to the group, and queues bot callbacks
+func (s *Server) newGroupMessage(g *group, u *user, m *groupme.Message) *groupme.Message {
+	msg := &groupme.Message{
+		ID:          s.newID(),
+		SourceGUID:  m.SourceGUID,
+		CreatedAt:   s.now(),
+		GroupID:     g.ID,
+		UserID:      u.ID,
+		SenderID:    u.ID,
+		SenderType:  groupme.SenderTypeUser,
+		Name:        u.Name,
+		AvatarURL:   u.ImageURL,
+		Text:        m.Text,
+		ImageURL:    m.ImageURL,
+		FavoritedBy: []string{},
+		Attachments: m.Attachments,
+	}
+	if member := g.member(u.ID); member != nil {
+		msg.Name = member.Nickname
+		msg.AvatarURL = member.ImageURL
+	}
+
+	s.addGroupMessage(g, msg)
+	return msg
+}
+
+// announce adds a system message to the group, if SystemMessages is set
+func (s *Server) announce(g *group, format string, args ...interface{}) {
+	if !s.SystemMessages {
+		return
+	}
+
+	s.addGroupMessage(g, &groupme.Message{
+		ID:          s.newID(),
+		CreatedAt:   s.now(),
+		GroupID:     g.ID,
+		UserID:      "system",
+		SenderID:    "system",
+		SenderType:  groupme.SenderTypeSystem,
+		System:      true,
+		Name:        "GroupMe",
+		Text:        fmt.Sprintf(format, args...),
+		FavoritedBy: []string{},
+	})
+}
+
+func (s *Server) addGroupMessage(g *group, msg *groupme.Message) {
+	g.messages = append(g.messages, msg)
+	g.UpdatedAt = msg.CreatedAt
+
+	for _, b := range s.bots {
+		if b.GroupID == g.ID && b.CallbackURL != "" {
+			var callbackMsg groupme.Message
+			clone(msg, &callbackMsg)
+			s.callbacks = append(s.callbacks, callback{url: b.CallbackURL, msg: &callbackMsg})
+		}
+	}
+	s.publishGroupMessage(g, msg)
+}
+
+/*//////// Bot Callbacks ////////*/
+
+type callback struct {
+	url string
+	msg *groupme.Message
+}
+
+// flushCallbacks removes the queued callbacks. Callers must hold the lock
+func (s *Server) flushCallbacks() []callback {
+	callbacks := s.callbacks
+	s.callbacks = nil
+	return callbacks
+}
+
+// deliverCallbacks posts each message to its bot callback URL, the way
+// GroupMe does. Callers must not hold the lock, so callback
+// handlers can make requests to the Server
+func (s *Server) deliverCallbacks(callbacks []callback) {
+	for _, cb := range callbacks {
+		body, err := json.Marshal(cb.msg)
+		if err != nil {
+			continue
+		}
+
+		resp, err := s.CallbackClient.Post(cb.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+	}
+}
+
+/*//////// Handlers ////////*/
+
+func (s *Server) indexMessages(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	page := pageMessages(g.messages, req)
+	if len(page) == 0 {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, groupme.IndexMessagesResponse{
+		Count:    len(g.messages),
+		Messages: page,
+	})
+}
+
+func (s *Server) createMessage(w http.ResponseWriter, req *http.Request, u *user) {
+	g := s.memberGroup(w, req, u)
+	if g == nil {
+		return
+	}
+
+	var data struct {
+		Message *groupme.Message `json:"message"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+	if data.Message == nil {
+		data.Message = &groupme.Message{}
+	}
+	if !validMessage(w, data.Message) {
+		return
+	}
+
+	msg := findBySourceGUID(g.messages, data.Message.SourceGUID)
+	if msg == nil {
+		msg = s.newGroupMessage(g, u, data.Message)
+	}
+	writeResponse(w, http.StatusCreated, map[string]interface{}{"message": msg})
+}
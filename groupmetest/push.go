@@ -0,0 +1,311 @@
+package groupmetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/gorilla/websocket"
+)
+
+// GroupMe documentation: https://dev.groupme.com/tutorials/push
+
+// pushPath is the path of the Faye (Bayeux protocol) push service
+const pushPath = "/faye"
+
+// PushBaseURL returns the Server equivalent of groupme.GroupMePushBase
+func (s *Server) PushBaseURL() string {
+	return s.URL + pushPath
+}
+
+type bayeuxMessage struct {
+	Channel                  string          `json:"channel"`
+	ID                       string          `json:"id,omitempty"`
+	ClientID                 string          `json:"clientId,omitempty"`
+	Version                  string          `json:"version,omitempty"`
+	SupportedConnectionTypes []string        `json:"supportedConnectionTypes,omitempty"`
+	ConnectionType           string          `json:"connectionType,omitempty"`
+	Subscription             string          `json:"subscription,omitempty"`
+	Successful               *bool           `json:"successful,omitempty"`
+	Error                    string          `json:"error,omitempty"`
+	Advice                   *bayeuxAdvice   `json:"advice,omitempty"`
+	Ext                      *bayeuxExt      `json:"ext,omitempty"`
+	Data                     json.RawMessage `json:"data,omitempty"`
+}
+
+type bayeuxAdvice struct {
+	Reconnect string `json:"reconnect,omitempty"`
+	Interval  int    `json:"interval"`
+	Timeout   int    `json:"timeout,omitempty"`
+}
+
+type bayeuxExt struct {
+	AccessToken string `json:"access_token,omitempty"`
+}
+
+type pushSubscriber struct {
+	channels map[string]bool
+	queue    []bayeuxMessage
+	notify   chan struct{}
+}
+
+func reply(msg bayeuxMessage, successful bool) bayeuxMessage {
+	return bayeuxMessage{
+		Channel:      msg.Channel,
+		ID:           msg.ID,
+		ClientID:     msg.ClientID,
+		Subscription: msg.Subscription,
+		Successful:   &successful,
+	}
+}
+
+/*//////// Publishing ////////*/
+
+// Publish delivers data, such as a typing event, to every push client subscribed to the channel
+func (s *Server) Publish(channel string, data interface{}) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+
+	s.pushMu.Lock()
+	defer s.pushMu.Unlock()
+
+	for _, subscriber := range s.pushSubscribers {
+		if !subscriber.channels[channel] {
+			continue
+		}
+
+		subscriber.queue = append(subscriber.queue, bayeuxMessage{Channel: channel, Data: jsonBytes})
+		select {
+		case subscriber.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func alert(msg *groupme.Message) string {
+	return fmt.Sprintf("%s: %s", msg.Name, msg.Text)
+}
+
+// publishGroupMessage sends line.create to every member of the group
+func (s *Server) publishGroupMessage(g *group, msg *groupme.Message) {
+	data := map[string]interface{}{
+		"type":    groupme.PushLineCreate,
+		"alert":   alert(msg),
+		"subject": msg,
+	}
+	for _, member := range g.members {
+		s.Publish("/user/"+member.UserID, data)
+	}
+}
+
+// publishDirectMessage sends direct_message.create to both users and the conversation
+func (s *Server) publishDirectMessage(c *chat, msg *groupme.Message) {
+	data := map[string]interface{}{
+		"type":    groupme.PushDirectMessageCreate,
+		"alert":   alert(msg),
+		"subject": msg,
+	}
+	s.Publish("/user/"+c.userIDs[0], data)
+	s.Publish("/user/"+c.userIDs[1], data)
+	s.Publish("/direct_message/"+strings.ReplaceAll(c.conversationID, "+", "_"), data)
+}
+
+// publishLike sends like.create to the author of the liked message
+func (s *Server) publishLike(msg *groupme.Message, userID string) {
+	if msg.UserID == "" {
+		return
+	}
+	s.Publish("/user/"+msg.UserID, map[string]interface{}{
+		"type": groupme.PushLikeCreate,
+		"subject": map[string]interface{}{
+			"line":    msg,
+			"user_id": userID,
+		},
+	})
+}
+
+// publishMembership sends membership.create to the user who joined the group
+func (s *Server) publishMembership(g *group, userID string) {
+	s.Publish("/user/"+userID, map[string]interface{}{
+		"type":    groupme.PushMembershipCreate,
+		"subject": s.groupResponse(g, true),
+	})
+}
+
+/*//////// Bayeux ////////*/
+
+// authorizedChannel reports whether the token's user may subscribe to the channel
+func (s *Server) authorizedChannel(token, channel string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.tokens[token]
+	if !ok {
+		return false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(channel, "/"), "/")
+	if len(parts) != 2 {
+		return false
+	}
+
+	switch parts[0] {
+	case "user":
+		return parts[1] == userID
+	case "group":
+		g, ok := s.groups[parts[1]]
+		return ok && g.member(userID) != nil
+	case "direct_message":
+		c, ok := s.chats[strings.ReplaceAll(parts[1], "_", "+")]
+		return !ok || c.userIDs[0] == userID || c.userIDs[1] == userID
+	}
+	return false
+}
+
+func (s *Server) handleBayeux(ctx context.Context, msgs []bayeuxMessage) []bayeuxMessage {
+	var replies []bayeuxMessage
+	for _, msg := range msgs {
+		switch msg.Channel {
+		case "/meta/handshake":
+			s.pushMu.Lock()
+			s.nextPushID++
+			clientID := fmt.Sprintf("client%d", s.nextPushID)
+			s.pushSubscribers[clientID] = &pushSubscriber{
+				channels: map[string]bool{},
+				notify:   make(chan struct{}, 1),
+			}
+			s.pushMu.Unlock()
+
+			r := reply(msg, true)
+			r.ClientID = clientID
+			r.Version = "1.0"
+			r.SupportedConnectionTypes = []string{"long-polling", "websocket"}
+			r.Advice = &bayeuxAdvice{Reconnect: "retry", Timeout: int(s.PushTimeout / time.Millisecond)}
+			replies = append(replies, r)
+
+		case "/meta/subscribe":
+			var token string
+			if msg.Ext != nil {
+				token = msg.Ext.AccessToken
+			}
+			authorized := s.authorizedChannel(token, msg.Subscription)
+
+			s.pushMu.Lock()
+			subscriber, ok := s.pushSubscribers[msg.ClientID]
+			if ok && authorized {
+				subscriber.channels[msg.Subscription] = true
+			}
+			s.pushMu.Unlock()
+
+			r := reply(msg, ok && authorized)
+			if !ok {
+				r.Error = "401::Unknown client"
+			} else if !authorized {
+				r.Error = "401::Unauthorized"
+			}
+			replies = append(replies, r)
+
+		case "/meta/connect":
+			replies = append(replies, s.connect(ctx, msg)...)
+
+		case "/meta/disconnect":
+			s.pushMu.Lock()
+			delete(s.pushSubscribers, msg.ClientID)
+			s.pushMu.Unlock()
+			replies = append(replies, reply(msg, true))
+
+		default:
+			r := reply(msg, false)
+			r.Error = "403::Publishing is not supported"
+			replies = append(replies, r)
+		}
+	}
+	return replies
+}
+
+// connect waits until there are messages for the client, or the
+// push timeout, and replies with the queued messages
+func (s *Server) connect(ctx context.Context, msg bayeuxMessage) []bayeuxMessage {
+	s.pushMu.Lock()
+	subscriber, ok := s.pushSubscribers[msg.ClientID]
+	s.pushMu.Unlock()
+
+	if !ok {
+		r := reply(msg, false)
+		r.Error = "401::Unknown client"
+		r.Advice = &bayeuxAdvice{Reconnect: "handshake"}
+		return []bayeuxMessage{r}
+	}
+
+	timer := time.NewTimer(s.PushTimeout)
+	defer timer.Stop()
+	for {
+		s.pushMu.Lock()
+		queue := subscriber.queue
+		subscriber.queue = nil
+		s.pushMu.Unlock()
+
+		if len(queue) > 0 {
+			return append([]bayeuxMessage{reply(msg, true)}, queue...)
+		}
+
+		select {
+		case <-subscriber.notify:
+		case <-timer.C:
+			return []bayeuxMessage{reply(msg, true)}
+		case <-ctx.Done():
+			return []bayeuxMessage{reply(msg, true)}
+		}
+	}
+}
+
+/*//////// Handlers ////////*/
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+func (s *Server) faye(w http.ResponseWriter, req *http.Request) {
+	if websocket.IsWebSocketUpgrade(req) {
+		s.fayeWebSocket(w, req)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msgs []bayeuxMessage
+	if err := json.NewDecoder(req.Body).Decode(&msgs); err != nil {
+		http.Error(w, "invalid Bayeux messages", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.handleBayeux(req.Context(), msgs))
+}
+
+func (s *Server) fayeWebSocket(w http.ResponseWriter, req *http.Request) {
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for {
+		var msgs []bayeuxMessage
+		if err := conn.ReadJSON(&msgs); err != nil {
+			return
+		}
+		if err := conn.WriteJSON(s.handleBayeux(req.Context(), msgs)); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// Package groupmetest provides an in-memory GroupMe server for testing
+// code built on the groupme package.
+//
+// The Server implements the v3 API, the image service and the push
+// service against in-memory state, which can be seeded and inspected directly:
+//
+//	server := groupmetest.NewServer()
+//	defer server.Close()
+//
+//	token := server.AddUser(groupme.User{Name: "Test User"})
+//	client := groupme.NewClient(token, server.ClientOptions()...)
+package groupmetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/gorilla/mux"
+)
+
+// apiPathPrefix matches the path of groupme.GroupMeAPIBase
+const apiPathPrefix = "/v3"
+
+// Server is a stateful, in-memory GroupMe server
+type Server struct {
+	*httptest.Server
+
+	// Now returns the time used for created and updated timestamps.
+	// Defaults to time.Now
+	Now func() time.Time
+	// CallbackClient posts messages to bot callback URLs
+	CallbackClient *http.Client
+	// PushTimeout is how long a push connect waits for events. Defaults to 30 seconds
+	PushTimeout time.Duration
+	// SystemMessages posts the system messages GroupMe sends to announce
+	// members joining, leaving and changing nicknames. Off by default
+	SystemMessages bool
+	// PendingAddResults is how many requests for the results of each add
+	// are answered 503, as if the adds were still processing
+	PendingAddResults int
+
+	mu         sync.Mutex
+	nextID     int
+	users      map[string]*user
+	tokens     map[string]string
+	groups     map[string]*group
+	chats      map[string]*chat
+	bots       map[string]*bot
+	blocks     []*groupme.Block
+	addResults map[string][]*groupme.Member
+	addPolls   map[string]int
+	pictures   map[string]*picture
+	callbacks  []callback
+
+	// pushMu guards the push clients. It may be taken while holding mu, never the reverse
+	pushMu          sync.Mutex
+	nextPushID      int
+	pushSubscribers map[string]*pushSubscriber
+}
+
+// NewServer starts a new, empty Server. It should be closed when finished
+func NewServer() *Server {
+	s := &Server{
+		Now:            time.Now,
+		CallbackClient: &http.Client{Timeout: 10 * time.Second},
+		PushTimeout:    30 * time.Second,
+		users:          map[string]*user{},
+		tokens:         map[string]string{},
+		groups:         map[string]*group{},
+		chats:          map[string]*chat{},
+		bots:           map[string]*bot{},
+		addResults:     map[string][]*groupme.Member{},
+		addPolls:       map[string]int{},
+		pictures:       map[string]*picture{},
+
+		pushSubscribers: map[string]*pushSubscriber{},
+	}
+	s.Server = httptest.NewServer(s.router())
+
+	return s
+}
+
+// HTTPClient returns an http.Client that sends every request, whatever its
+// host, to the Server. Pass it to groupme.WithHTTPClient to point a client
+// at the Server.
+func (s *Server) HTTPClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{
+		Transport: rewriteTransport{target: target, base: s.Client().Transport},
+	}
+}
+
+// ClientOptions returns the options pointing a groupme Client or BotClient at the Server
+func (s *Server) ClientOptions() []groupme.ClientOption {
+	return []groupme.ClientOption{
+		groupme.WithAPIBaseURL(s.APIBaseURL()),
+		groupme.WithImageBaseURL(s.ImageBaseURL()),
+		groupme.WithPushBaseURL(s.PushBaseURL()),
+	}
+}
+
+// APIBaseURL returns the Server equivalent of groupme.GroupMeAPIBase
+func (s *Server) APIBaseURL() string {
+	return s.URL + apiPathPrefix
+}
+
+// ImageBaseURL returns the Server equivalent of groupme.GroupMeImageBase
+func (s *Server) ImageBaseURL() string {
+	return s.URL
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.base.RoundTrip(req)
+}
+
+/*//////// Routing ////////*/
+
+func (s *Server) router() *mux.Router {
+	router := mux.NewRouter()
+
+	// Image service
+	router.Path("/pictures").Methods("POST").HandlerFunc(s.authenticated(s.uploadPicture))
+	router.Path("/pictures/{id}").Methods("GET").HandlerFunc(s.locked(s.showPicture))
+
+	// Push service, which waits for events without holding the Server lock
+	router.Path(pushPath).HandlerFunc(s.faye)
+
+	api := router.PathPrefix(apiPathPrefix).Subrouter()
+
+	// Bots post without a token
+	api.Path("/bots/post").Methods("POST").HandlerFunc(s.locked(s.postBotMessage))
+
+	routes := []struct {
+		method  string
+		path    string
+		handler authenticatedHandler
+	}{
+		// Groups
+		{"GET", "/groups", s.indexGroups},
+		{"GET", "/groups/former", s.formerGroups},
+		{"POST", "/groups", s.createGroup},
+		{"POST", "/groups/join", s.rejoinGroup},
+		{"POST", "/groups/change_owners", s.changeGroupOwner},
+		{"GET", "/groups/{group_id}", s.showGroup},
+		{"POST", "/groups/{group_id}/update", s.updateGroup},
+		{"POST", "/groups/{group_id}/destroy", s.destroyGroup},
+		{"POST", "/groups/{group_id}/join/{share_token}", s.joinGroup},
+
+		// Members
+		{"POST", "/groups/{group_id}/members/add", s.addMembers},
+		{"GET", "/groups/{group_id}/members/results/{results_id}", s.addMembersResults},
+		{"POST", "/groups/{group_id}/members/{membership_id}/remove", s.removeMember},
+		{"POST", "/groups/{group_id}/memberships/update", s.updateMember},
+
+		// Messages
+		{"GET", "/groups/{group_id}/messages", s.indexMessages},
+		{"POST", "/groups/{group_id}/messages", s.createMessage},
+
+		// Direct Messages
+		{"GET", "/direct_messages", s.indexDirectMessages},
+		{"POST", "/direct_messages", s.createDirectMessage},
+
+		// Chats
+		{"GET", "/chats", s.indexChats},
+
+		// Likes
+		{"POST", "/messages/{conversation_id}/{message_id}/like", s.createLike},
+		{"POST", "/messages/{conversation_id}/{message_id}/unlike", s.destroyLike},
+
+		// Leaderboard
+		{"GET", "/groups/{group_id}/likes", s.indexLeaderboard},
+		{"GET", "/groups/{group_id}/likes/mine", s.myLikesLeaderboard},
+		{"GET", "/groups/{group_id}/likes/for_me", s.myHitsLeaderboard},
+
+		// Bots
+		{"POST", "/bots", s.createBot},
+		{"GET", "/bots", s.indexBots},
+		{"POST", "/bots/destroy", s.destroyBot},
+
+		// Blocks
+		{"GET", "/blocks", s.indexBlocks},
+		{"GET", "/blocks/between", s.blockBetween},
+		{"POST", "/blocks", s.createBlock},
+		{"DELETE", "/blocks", s.unblock},
+
+		// Users
+		{"GET", "/users/me", s.myUser},
+		{"POST", "/users/update", s.updateMyUser},
+
+		// SMS Mode
+		{"POST", "/users/sms_mode", s.createSMSMode},
+		{"POST", "/users/sms_mode/delete", s.deleteSMSMode},
+	}
+	for _, route := range routes {
+		api.Path(route.path).Methods(route.method).HandlerFunc(s.authenticated(route.handler))
+	}
+
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeError(w, http.StatusNotFound, "endpoint not found")
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeError(w, http.StatusNotFound, "endpoint not found")
+	})
+
+	return router
+}
+
+// authenticatedHandler handles a request made by the user owning the request token.
+// The Server lock is held while it runs
+type authenticatedHandler func(w http.ResponseWriter, req *http.Request, u *user)
+
+func (s *Server) authenticated(handler authenticatedHandler) http.HandlerFunc {
+	return s.locked(func(w http.ResponseWriter, req *http.Request) {
+		userID, ok := s.tokens[req.URL.Query().Get("token")]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		handler(w, req, s.users[userID])
+	})
+}
+
+// locked runs the handler holding the Server lock, then delivers
+// any bot callbacks queued by the handler
+func (s *Server) locked(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		handler(w, req)
+		callbacks := s.flushCallbacks()
+		s.mu.Unlock()
+
+		s.deliverCallbacks(callbacks)
+	}
+}
+
+/*//////// Responses ////////*/
+
+func writeResponse(w http.ResponseWriter, code int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Response interface{}  `json:"response"`
+		Meta     groupme.Meta `json:"meta"`
+	}{response, groupme.Meta{Code: code}})
+}
+
+func writeError(w http.ResponseWriter, code int, errors ...string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Response interface{}  `json:"response"`
+		Meta     groupme.Meta `json:"meta"`
+	}{nil, groupme.Meta{Code: code, Errors: errors}})
+}
+
+func decodeBody(w http.ResponseWriter, req *http.Request, i interface{}) bool {
+	if err := json.NewDecoder(req.Body).Decode(i); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+/*//////// Helpers ////////*/
+
+// newID returns a new unique, increasing, numeric ID. Callers must hold the lock
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+func (s *Server) now() groupme.Timestamp {
+	return groupme.FromTime(s.Now())
+}
+
+// queryInt returns the integer query parameter, or def if absent or invalid
+func queryInt(req *http.Request, key string, def int) int {
+	value, err := strconv.Atoi(req.URL.Query().Get(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// paginate returns the page of items, with pages numbered from 1
+func paginate(count, page, perPage int) (start, end int) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	start = (page - 1) * perPage
+	if start > count {
+		start = count
+	}
+	end = start + perPage
+	if end > count {
+		end = count
+	}
+	return start, end
+}
+
+// clone deep copies src into dst through JSON, so callers can't mutate Server state
+func clone(src, dst interface{}) {
+	bytes, err := json.Marshal(src)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(bytes, dst); err != nil {
+		panic(err)
+	}
+}
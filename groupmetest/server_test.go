@@ -0,0 +1,391 @@
+package groupmetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServerSuite struct {
+	suite.Suite
+	server *Server
+
+	alice, bob, carol                *groupme.User
+	aliceClient, bobClient           *groupme.Client
+	aliceToken, bobToken, carolToken string
+}
+
+func (s *ServerSuite) SetupTest() {
+	s.server = NewServer()
+
+	s.aliceToken = s.server.AddUser(groupme.User{Name: "Alice", Email: "alice@example.com"})
+	s.bobToken = s.server.AddUser(groupme.User{Name: "Bob", PhoneNumber: "+1 5555555555"})
+	s.carolToken = s.server.AddUser(groupme.User{Name: "Carol"})
+
+	s.aliceClient = s.newClient(s.aliceToken)
+	s.bobClient = s.newClient(s.bobToken)
+
+	var err error
+	s.alice, err = s.aliceClient.MyUser(context.Background())
+	s.Require().NoError(err)
+	s.bob, err = s.bobClient.MyUser(context.Background())
+	s.Require().NoError(err)
+	s.carol, err = s.newClient(s.carolToken).MyUser(context.Background())
+	s.Require().NoError(err)
+}
+
+func (s *ServerSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ServerSuite) newClient(token string) *groupme.Client {
+	return groupme.NewClient(token, groupme.WithHTTPClient(s.server.HTTPClient()))
+}
+
+func (s *ServerSuite) TestClientOptions() {
+	user, err := groupme.NewClient(s.aliceToken, s.server.ClientOptions()...).MyUser(context.Background())
+	s.Require().NoError(err)
+	s.Assert().Equal(s.alice.ID, user.ID)
+}
+
+func (s *ServerSuite) TestUnauthorized() {
+	_, err := s.newClient("bad token").MyUser(context.Background())
+	s.Assert().True(errors.Is(err, groupme.ErrUnauthorized))
+}
+
+func (s *ServerSuite) TestGroupFlow() {
+	ctx := context.Background()
+
+	group, err := s.aliceClient.CreateGroup(ctx, groupme.GroupSettings{Name: "Test", Share: true})
+	s.Require().NoError(err)
+	s.Assert().Equal(s.alice.ID, group.CreatorUserID)
+	s.Assert().NotEmpty(group.ShareURL)
+	s.Require().Len(group.Members, 1)
+
+	// Add members by user ID and phone number
+	resultsID, err := s.aliceClient.AddMembers(ctx, group.ID,
+		&groupme.Member{Nickname: "Bobby", PhoneNumber: "+1 5555555555", GUID: "bob"},
+		&groupme.Member{Nickname: "Nobody", UserID: "999", GUID: "nobody"},
+	)
+	s.Require().NoError(err)
+	results, err := s.aliceClient.AddMembersResults(ctx, group.ID, resultsID)
+	s.Require().NoError(err)
+	s.Require().Len(results, 1)
+	s.Assert().Equal("bob", results[0].GUID)
+	s.Assert().Equal(s.bob.ID, results[0].UserID)
+
+	// Post, like and rank
+	msg, err := s.bobClient.CreateMessage(ctx, group.ID, &groupme.Message{Text: "Hello"})
+	s.Require().NoError(err)
+	s.Assert().Equal("Bobby", msg.Name)
+	s.Require().NoError(s.aliceClient.CreateLike(ctx, group.ID, msg.ID))
+
+	leaderboard, err := s.aliceClient.IndexLeaderboard(ctx, group.ID, groupme.PeriodDay)
+	s.Require().NoError(err)
+	s.Require().Len(leaderboard, 1)
+	s.Assert().Equal([]string{s.alice.ID}, leaderboard[0].FavoritedBy)
+
+	mine, err := s.aliceClient.MyLikesLeaderboard(ctx, group.ID)
+	s.Require().NoError(err)
+	s.Assert().Len(mine, 1)
+	hits, err := s.bobClient.MyHitsLeaderboard(ctx, group.ID)
+	s.Require().NoError(err)
+	s.Assert().Len(hits, 1)
+
+	s.Require().NoError(s.aliceClient.DestroyLike(ctx, group.ID, msg.ID))
+	s.Assert().Empty(s.server.Messages(group.ID)[0].FavoritedBy)
+
+	// Listing and paging
+	groups, err := s.bobClient.IndexGroups(ctx, nil)
+	s.Require().NoError(err)
+	s.Require().Len(groups, 1)
+	s.Assert().Equal(msg.ID, groups[0].Messages.LastMessageID)
+
+	index, err := s.bobClient.IndexMessages(ctx, group.ID, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal(1, index.Count)
+
+	_, err = s.bobClient.IndexMessages(ctx, group.ID, &groupme.IndexMessagesQuery{BeforeID: msg.ID})
+	s.Assert().True(errors.Is(err, groupme.ErrNotModified))
+
+	// Nickname, leave, rejoin
+	member, err := s.bobClient.UpdateMember(ctx, group.ID, "Robert")
+	s.Require().NoError(err)
+	s.Assert().Equal("Robert", member.Nickname)
+
+	s.Require().NoError(s.bobClient.RemoveMember(ctx, group.ID, member.ID))
+	former, err := s.bobClient.FormerGroups(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(former, 1)
+
+	_, err = s.bobClient.RejoinGroup(ctx, group.ID)
+	s.Require().NoError(err)
+	s.Assert().NotNil(s.server.Group(group.ID).GetMemberByUserID(s.bob.ID))
+
+	// The creator can't be removed
+	creator := s.server.Group(group.ID).GetMemberByUserID(s.alice.ID)
+	s.Assert().Error(s.aliceClient.RemoveMember(ctx, group.ID, creator.ID))
+
+	// Ownership and destruction
+	result, err := s.aliceClient.ChangeGroupOwner(ctx, groupme.ChangeOwnerRequest{GroupID: group.ID, OwnerID: s.bob.ID})
+	s.Require().NoError(err)
+	s.Assert().Equal(groupme.ChangeOwnerOk, result.Status)
+
+	s.Assert().Error(s.aliceClient.DestroyGroup(ctx, group.ID))
+	s.Require().NoError(s.bobClient.DestroyGroup(ctx, group.ID))
+	s.Assert().Nil(s.server.Group(group.ID))
+}
+
+func (s *ServerSuite) TestJoinGroup() {
+	ctx := context.Background()
+
+	group := s.server.AddGroup(s.alice.ID, groupme.GroupSettings{Name: "Shared", Share: true})
+	token := path.Base(group.ShareURL)
+
+	_, err := s.bobClient.JoinGroup(ctx, group.ID, "wrong")
+	s.Assert().True(errors.Is(err, groupme.ErrNotFound))
+
+	joined, err := s.bobClient.JoinGroup(ctx, group.ID, token)
+	s.Require().NoError(err)
+	s.Assert().Len(joined.Members, 2)
+
+	updated, err := s.aliceClient.UpdateGroup(ctx, group.ID, groupme.GroupSettings{Name: "Renamed"})
+	s.Require().NoError(err)
+	s.Assert().Equal("Renamed", updated.Name)
+	s.Assert().Empty(updated.ShareURL)
+}
+
+func (s *ServerSuite) TestDirectMessages() {
+	ctx := context.Background()
+
+	msg, err := s.aliceClient.CreateDirectMessage(ctx, &groupme.Message{RecipientID: s.bob.ID, Text: "Hi Bob"})
+	s.Require().NoError(err)
+	s.Assert().Equal(groupme.ConversationID(s.alice.ID, s.bob.ID), msg.ConversationID)
+
+	index, err := s.bobClient.IndexDirectMessages(ctx, s.alice.ID, nil)
+	s.Require().NoError(err)
+	s.Require().Len(index.Messages, 1)
+	s.Assert().Equal("Hi Bob", index.Messages[0].Text)
+
+	_, err = s.bobClient.IndexDirectMessages(ctx, s.alice.ID, &groupme.IndexDirectMessagesQuery{SinceID: msg.ID})
+	s.Assert().True(errors.Is(err, groupme.ErrNotModified))
+
+	chats, err := s.bobClient.IndexChats(ctx, nil)
+	s.Require().NoError(err)
+	s.Require().Len(chats, 1)
+	s.Assert().Equal(s.alice.ID, chats[0].OtherUser.ID)
+
+	s.Require().NoError(s.bobClient.CreateLike(ctx, msg.ConversationID, msg.ID))
+	s.Assert().Equal([]string{s.bob.ID}, s.server.DirectMessages(s.alice.ID, s.bob.ID)[0].FavoritedBy)
+
+	// Blocked users can't DM
+	_, err = s.bobClient.CreateBlock(ctx, s.bob.ID, s.alice.ID)
+	s.Require().NoError(err)
+	_, err = s.aliceClient.CreateDirectMessage(ctx, &groupme.Message{RecipientID: s.bob.ID, Text: "Hi again"})
+	s.Assert().Error(err)
+}
+
+func (s *ServerSuite) TestBots() {
+	ctx := context.Background()
+
+	received := make(chan groupme.Message, 10)
+	callbackServer := httptest.NewServer(groupme.HTTPHandlerFunc(func(msg groupme.Message) {
+		received <- msg
+	}))
+	defer callbackServer.Close()
+
+	group := s.server.AddGroup(s.alice.ID, groupme.GroupSettings{Name: "Bots"}, s.bob.ID)
+
+	bot, err := s.aliceClient.CreateBot(ctx, &groupme.Bot{Name: "hal9000", GroupID: group.ID, CallbackURL: callbackServer.URL})
+	s.Require().NoError(err)
+
+	bots, err := s.aliceClient.IndexBots(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(bots, 1)
+
+	// Member messages are sent to the callback
+	_, err = s.bobClient.CreateMessage(ctx, group.ID, &groupme.Message{Text: "Hello bot"})
+	s.Require().NoError(err)
+	s.Require().Len(received, 1)
+	s.Assert().Equal("Hello bot", (<-received).Text)
+
+	// Bot messages too
+	botClient := groupme.NewBotClient(bot.BotID, groupme.WithHTTPClient(s.server.HTTPClient()))
+	s.Require().NoError(botClient.PostBotMessage(ctx, "Hello humans", nil))
+	s.Require().Len(received, 1)
+	callbackMsg := <-received
+	s.Assert().Equal(groupme.SenderTypeBot, callbackMsg.SenderType)
+
+	s.Require().NoError(s.aliceClient.DestroyBot(ctx, bot.BotID))
+	s.Assert().Empty(s.server.Bots())
+}
+
+func (s *ServerSuite) TestBlocks() {
+	ctx := context.Background()
+
+	block, err := s.aliceClient.CreateBlock(ctx, s.alice.ID, s.carol.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(s.carol.ID, block.BlockedUserID)
+
+	between, err := s.aliceClient.BlockBetween(ctx, s.alice.ID, s.carol.ID)
+	s.Require().NoError(err)
+	s.Assert().True(between)
+
+	blocks, err := s.aliceClient.IndexBlock(ctx, s.alice.ID)
+	s.Require().NoError(err)
+	s.Assert().Len(blocks, 1)
+
+	s.Require().NoError(s.aliceClient.Unblock(ctx, s.alice.ID, s.carol.ID))
+	s.Assert().Empty(s.server.Blocks())
+}
+
+func (s *ServerSuite) TestUsersAndSMSMode() {
+	ctx := context.Background()
+
+	user, err := s.aliceClient.UpdateMyUser(ctx, groupme.UserSettings{Name: "Alice Smith"})
+	s.Require().NoError(err)
+	s.Assert().Equal("Alice Smith", user.Name)
+	s.Assert().Equal("Alice Smith", s.server.User(s.alice.ID).Name)
+
+	s.Assert().Error(s.aliceClient.CreateSMSMode(ctx, 49, nil))
+	s.Require().NoError(s.aliceClient.CreateSMSMode(ctx, 10, nil))
+	s.Assert().Equal(&SMSMode{Duration: 10}, s.server.SMSMode(s.alice.ID))
+	s.Require().NoError(s.aliceClient.DeleteSMSMode(ctx))
+	s.Assert().Nil(s.server.SMSMode(s.alice.ID))
+}
+
+func (s *ServerSuite) TestUploadPicture() {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+
+	picture, err := s.aliceClient.UploadPicture(context.Background(), img, groupme.PictureEncodingPNG)
+	s.Require().NoError(err)
+
+	contentType, data, ok := s.server.Picture(picture.Base)
+	s.Require().True(ok)
+	s.Assert().Equal("image/png", contentType)
+	s.Assert().True(bytes.HasPrefix(data, []byte("\x89PNG")))
+}
+
+func (s *ServerSuite) TestValidatingClient() {
+	ctx := context.Background()
+	client := groupme.NewClient(s.aliceToken, append(s.server.ClientOptions(), groupme.WithValidation())...)
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+
+	// Pictures are hosted by the Server, standing in for the image service
+	picture, err := client.UploadPicture(ctx, img, groupme.PictureEncodingPNG)
+	s.Require().NoError(err)
+	group, err := client.CreateGroup(ctx, groupme.GroupSettings{Name: "Team", ImageURL: picture.Base})
+	s.Require().NoError(err)
+	_, err = client.CreateMessage(ctx, group.ID, &groupme.Message{Text: "See", ImageURL: picture.Large})
+	s.Require().NoError(err)
+
+	_, err = client.CreateMessage(ctx, group.ID, &groupme.Message{ImageURL: "https://example.com/1"})
+	var validationErr *groupme.ValidationError
+	s.Require().True(errors.As(err, &validationErr), err)
+	s.Assert().NotNil(validationErr.Field("image_url"))
+}
+
+// subscribed reports whether any push client is subscribed to the channel
+func (s *ServerSuite) subscribed(channel string) bool {
+	s.server.pushMu.Lock()
+	defer s.server.pushMu.Unlock()
+	for _, subscriber := range s.server.pushSubscribers {
+		if subscriber.channels[channel] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ServerSuite) testPush(transport groupme.PushClientOption) {
+	s.server.PushTimeout = 100 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	group := s.server.AddGroup(s.alice.ID, groupme.GroupSettings{Name: "Push"}, s.bob.ID)
+
+	push := groupme.NewPushClient(s.aliceToken, transport, groupme.WithPushClientOptions(s.server.ClientOptions()...))
+	push.SubscribeUser(s.alice.ID)
+	push.SubscribeGroup(group.ID)
+	done := make(chan error)
+	go func() { done <- push.Run(ctx) }()
+
+	s.Require().Eventually(func() bool {
+		return s.subscribed("/user/"+s.alice.ID) && s.subscribed("/group/"+group.ID)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	next := func() *groupme.PushEvent {
+		select {
+		case event := <-push.Events():
+			return event
+		case <-time.After(5 * time.Second):
+			s.FailNow("timed out waiting for a push event")
+			return nil
+		}
+	}
+
+	msg, err := s.bobClient.CreateMessage(ctx, group.ID, &groupme.Message{Text: "Hello"})
+	s.Require().NoError(err)
+	event := next()
+	s.Assert().Equal(groupme.PushLineCreate, event.Type)
+	s.Assert().Equal(msg.ID, event.Message.ID)
+
+	s.Require().NoError(s.bobClient.CreateLike(ctx, group.ID, s.server.AddMessage(group.ID, s.alice.ID, "Like me").ID))
+	event = next()
+	s.Assert().Equal(groupme.PushLineCreate, event.Type)
+	event = next()
+	s.Assert().Equal(groupme.PushLikeCreate, event.Type)
+	s.Assert().Equal(s.bob.ID, event.UserID)
+
+	_, err = s.bobClient.CreateDirectMessage(ctx, &groupme.Message{RecipientID: s.alice.ID, Text: "Hi"})
+	s.Require().NoError(err)
+	event = next()
+	s.Assert().Equal(groupme.PushDirectMessageCreate, event.Type)
+	s.Assert().Equal("Hi", event.Message.Text)
+
+	s.server.Publish("/group/"+group.ID, map[string]interface{}{"type": "typing", "user_id": s.bob.ID})
+	event = next()
+	s.Assert().Equal(groupme.PushTyping, event.Type)
+	s.Assert().Equal("/group/"+group.ID, event.Channel)
+
+	cancel()
+	s.Assert().True(errors.Is(<-done, context.Canceled))
+}
+
+func (s *ServerSuite) TestPushLongPolling() {
+	s.testPush(groupme.WithPushTransport(groupme.PushTransportLongPolling))
+}
+
+func (s *ServerSuite) TestPushWebSocket() {
+	s.testPush(groupme.WithPushTransport(groupme.PushTransportWebSocket))
+}
+
+func (s *ServerSuite) TestPushUnauthorizedSubscribe() {
+	ctx := context.Background()
+	group := s.server.AddGroup(s.alice.ID, groupme.GroupSettings{Name: "Private"})
+
+	messages := s.server.handleBayeux(ctx, []bayeuxMessage{{Channel: "/meta/handshake"}})
+	clientID := messages[0].ClientID
+
+	for _, channel := range []string{"/user/" + s.alice.ID, "/group/" + group.ID} {
+		messages = s.server.handleBayeux(ctx, []bayeuxMessage{{
+			Channel:      "/meta/subscribe",
+			ClientID:     clientID,
+			Subscription: channel,
+			Ext:          &bayeuxExt{AccessToken: s.bobToken},
+		}})
+		s.Assert().False(*messages[0].Successful, channel)
+	}
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(ServerSuite))
+}
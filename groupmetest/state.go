@@ -0,0 +1,255 @@
+package groupmetest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/densestvoid/groupme"
+)
+
+/*//////// State ////////*/
+
+type user struct {
+	groupme.User
+	token   string
+	zipCode string
+	smsMode *SMSMode
+}
+
+type group struct {
+	groupme.Group
+	shareToken string
+	members    []*groupme.Member
+	// User IDs of members who left or were removed
+	former   map[string]bool
+	messages []*groupme.Message
+}
+
+func (g *group) member(userID string) *groupme.Member {
+	for _, member := range g.members {
+		if member.UserID == userID {
+			return member
+		}
+	}
+	return nil
+}
+
+func (g *group) message(messageID string) *groupme.Message {
+	for _, msg := range g.messages {
+		if msg.ID == messageID {
+			return msg
+		}
+	}
+	return nil
+}
+
+type chat struct {
+	conversationID string
+	userIDs        [2]string
+	createdAt      groupme.Timestamp
+	updatedAt      groupme.Timestamp
+	messages       []*groupme.Message
+}
+
+func (c *chat) otherUserID(userID string) string {
+	if c.userIDs[0] == userID {
+		return c.userIDs[1]
+	}
+	return c.userIDs[0]
+}
+
+func (c *chat) message(messageID string) *groupme.Message {
+	for _, msg := range c.messages {
+		if msg.ID == messageID {
+			return msg
+		}
+	}
+	return nil
+}
+
+type bot struct {
+	groupme.Bot
+	ownerID string
+}
+
+type picture struct {
+	contentType string
+	data        []byte
+}
+
+// SMSMode is the SMS mode state of a user
+type SMSMode struct {
+	Duration       int
+	RegistrationID string
+}
+
+/*//////// Seeding ////////*/
+
+// AddUser adds a user, returning the token to authenticate as them.
+// An ID is assigned if the user has none
+func (s *Server) AddUser(u groupme.User) (token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u.ID == "" {
+		u.ID = s.newID()
+	}
+	if u.CreatedAt == 0 {
+		u.CreatedAt = s.now()
+		u.UpdatedAt = u.CreatedAt
+	}
+
+	token = fmt.Sprintf("token-%s", u.ID)
+	s.users[u.ID] = &user{User: u, token: token}
+	s.tokens[token] = u.ID
+
+	return token
+}
+
+// AddGroup creates a group owned by the creator, with the
+// given users as members. Returns the created group
+func (s *Server) AddGroup(creatorID string, settings groupme.GroupSettings, memberIDs ...string) *groupme.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.newGroup(s.users[creatorID], settings)
+	for _, memberID := range memberIDs {
+		if u, ok := s.users[memberID]; ok && g.member(memberID) == nil {
+			g.members = append(g.members, s.newMember(u, ""))
+		}
+	}
+
+	return s.groupResponse(g, false)
+}
+
+// AddMessage posts a message to a group as the user. Bot callbacks are not called.
+// Returns the created message
+func (s *Server) AddMessage(groupID, userID, text string) *groupme.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.groups[groupID]
+	if !ok {
+		return nil
+	}
+
+	msg := s.newGroupMessage(g, s.users[userID], &groupme.Message{Text: text})
+	s.flushCallbacks()
+
+	var resp groupme.Message
+	clone(msg, &resp)
+	return &resp
+}
+
+/*//////// Inspection ////////*/
+
+// User returns a copy of the user, nil if it doesn't exist
+func (s *Server) User(userID string) *groupme.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return nil
+	}
+
+	var resp groupme.User
+	clone(&u.User, &resp)
+	return &resp
+}
+
+// Group returns a copy of the group including its members, nil if it doesn't exist
+func (s *Server) Group(groupID string) *groupme.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.groups[groupID]
+	if !ok {
+		return nil
+	}
+	return s.groupResponse(g, false)
+}
+
+// Messages returns copies of a group's messages, oldest first
+func (s *Server) Messages(groupID string) []*groupme.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.groups[groupID]
+	if !ok {
+		return nil
+	}
+
+	var resp []*groupme.Message
+	clone(g.messages, &resp)
+	return resp
+}
+
+// DirectMessages returns copies of the messages between two users, oldest first
+func (s *Server) DirectMessages(userID, otherUserID string) []*groupme.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.chats[groupme.ConversationID(userID, otherUserID)]
+	if !ok {
+		return nil
+	}
+
+	var resp []*groupme.Message
+	clone(c.messages, &resp)
+	return resp
+}
+
+// Bots returns copies of every bot, ordered by ID
+func (s *Server) Bots() []*groupme.Bot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := []*groupme.Bot{}
+	for _, b := range s.bots {
+		var botCopy groupme.Bot
+		clone(&b.Bot, &botCopy)
+		resp = append(resp, &botCopy)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return groupme.CompareIDs(resp[i].BotID, resp[j].BotID) < 0
+	})
+	return resp
+}
+
+// Blocks returns copies of every block
+func (s *Server) Blocks() []*groupme.Block {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var resp []*groupme.Block
+	clone(s.blocks, &resp)
+	return resp
+}
+
+// SMSMode returns the user's SMS mode, nil if disabled
+func (s *Server) SMSMode(userID string) *SMSMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok || u.smsMode == nil {
+		return nil
+	}
+
+	mode := *u.smsMode
+	return &mode
+}
+
+// Picture returns the content type and data of an uploaded picture by its URL
+func (s *Server) Picture(url string) (contentType string, data []byte, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, p := range s.pictures {
+		if url == s.pictureURL(id) {
+			return p.contentType, append([]byte(nil), p.data...), true
+		}
+	}
+	return "", nil, false
+}
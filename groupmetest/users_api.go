@@ -0,0 +1,72 @@
+package groupmetest
+
+import (
+	"net/http"
+
+	"github.com/densestvoid/groupme"
+)
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#users
+
+/*//////// Handlers ////////*/
+
+func (s *Server) myUser(w http.ResponseWriter, req *http.Request, u *user) {
+	writeResponse(w, http.StatusOK, u.User)
+}
+
+func (s *Server) updateMyUser(w http.ResponseWriter, req *http.Request, u *user) {
+	var settings groupme.UserSettings
+	if !decodeBody(w, req, &settings) {
+		return
+	}
+
+	if settings.AvatarURL != "" {
+		u.ImageURL = settings.AvatarURL
+		u.AvatarURL = settings.AvatarURL
+	}
+	if settings.Name != "" {
+		u.Name = settings.Name
+	}
+	if settings.Email != "" {
+		u.Email = settings.Email
+	}
+	if settings.ZipCode != "" {
+		u.zipCode = settings.ZipCode
+	}
+	u.UpdatedAt = s.now()
+
+	writeResponse(w, http.StatusOK, u.User)
+}
+
+// GroupMe documentation: https://dev.groupme.com/docs/v3#sms_mode
+
+const maxSMSModeDuration = 48
+
+func (s *Server) createSMSMode(w http.ResponseWriter, req *http.Request, u *user) {
+	var data struct {
+		Duration       int     `json:"duration"`
+		RegistrationID *string `json:"registration_id"`
+	}
+	if !decodeBody(w, req, &data) {
+		return
+	}
+	if data.Duration < 1 || data.Duration > maxSMSModeDuration {
+		writeError(w, http.StatusBadRequest, "duration must be between 1 and 48 hours")
+		return
+	}
+
+	u.smsMode = &SMSMode{Duration: data.Duration}
+	if data.RegistrationID != nil {
+		u.smsMode.RegistrationID = *data.RegistrationID
+	}
+	u.SMS = true
+
+	writeResponse(w, http.StatusCreated, nil)
+}
+
+func (s *Server) deleteSMSMode(w http.ResponseWriter, req *http.Request, u *user) {
+	u.smsMode = nil
+	u.SMS = false
+
+	writeResponse(w, http.StatusOK, nil)
+}
@@ -175,6 +175,10 @@ CreateGroup -
 Parameters: See GroupSettings
 */
 func (c *Client) CreateGroup(ctx context.Context, gs GroupSettings) (*Group, error) {
+	if err := c.validate(gs.validate); err != nil {
+		return nil, err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase + createGroupEndpoint)
 
 	jsonBytes, err := json.Marshal(&gs)
@@ -209,6 +213,10 @@ Parameters:
 	See GroupSettings
 */
 func (c *Client) UpdateGroup(ctx context.Context, groupID string, gs GroupSettings) (*Group, error) {
+	if err := c.validate(validID("group_id", groupID), gs.validate); err != nil {
+		return nil, err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase+updateGroupEndpoint, groupID)
 
 	jsonBytes, err := json.Marshal(&gs)
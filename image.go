@@ -3,12 +3,15 @@ package groupme
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
+	"io"
+	"mime"
 	"net/http"
+	"os"
 )
 
 const (
@@ -24,6 +27,30 @@ const (
 	PictureEncodingJPEG = "jpeg"
 )
 
+// Content-Types of the pictures accepted by the image service
+var pictureContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+var (
+	// ErrUnsupportedPicture is returned for pictures that aren't PNG, JPEG, GIF or WebP
+	ErrUnsupportedPicture = errors.New("groupme: unsupported picture format")
+	// ErrPictureTooLarge is returned for pictures over MaxPictureSize
+	ErrPictureTooLarge = fmt.Errorf("groupme: picture larger than %d bytes", MaxPictureSize)
+	// ErrNoPictureURL is returned when the image service accepts a picture
+	// without returning its URL
+	ErrNoPictureURL = errors.New("groupme: image service returned no picture URL")
+	// ErrNoImageToken is returned for uploads by a client without an image
+	// token, like a BotClient created without WithImageToken
+	ErrNoImageToken = errors.New("groupme: no image token, set one with WithImageToken")
+)
+
+// MaxPictureSize is the most bytes of a picture read for an upload
+const MaxPictureSize = 20 << 20
+
 // PictureURL contains URLS to an uploaded picture as well as the
 // various thumbnails provided by GroupMe.
 type PictureURL struct {
@@ -35,6 +62,8 @@ type PictureURL struct {
 
 // UploadPicture posts an image to the GroupMe image service. Accepts either PNG or JPEG.
 // Returns URLs to the uploaded image to be used in messages or avatars.
+//
+// The image is re-encoded. To upload a picture as is, use UploadPictureReader
 func (c *Client) UploadPicture(ctx context.Context, img image.Image, encoding PictureEncoding) (PictureURL, error) {
 	var imgBytes bytes.Buffer
 	var err error
@@ -51,16 +80,85 @@ func (c *Client) UploadPicture(ctx context.Context, img image.Image, encoding Pi
 		return PictureURL{}, fmt.Errorf("failed to encode image: %v", err)
 	}
 
-	httpReq, err := http.NewRequest(http.MethodPost, c.imageEndpointBase+uploadPictureEndpoint, &imgBytes)
+	return c.UploadPictureReader(ctx, &imgBytes, "image/"+string(encoding))
+}
+
+/*
+UploadPictureReader -
+
+Posts the picture read from r to the GroupMe image service as is,
+preserving GIF animation and metadata. Accepts PNG, JPEG, GIF and WebP.
+
+If contentType is empty or not an image type, it is detected from the
+content. Returns ErrUnsupportedPicture for other formats, and
+ErrPictureTooLarge for pictures over MaxPictureSize.
+
+Failed uploads return an *APIError and are retried by the client's
+RetryPolicy, like API requests.
+
+A BotClient needs WithImageToken to upload pictures
+*/
+func (c *client) UploadPictureReader(ctx context.Context, r io.Reader, contentType string) (PictureURL, error) {
+	// Read in full, so failed uploads can be retried
+	picture, err := io.ReadAll(io.LimitReader(r, MaxPictureSize+1))
 	if err != nil {
 		return PictureURL{}, err
 	}
-	httpReq.Header.Add("Content-Type", "image/"+string(encoding))
+	if len(picture) > MaxPictureSize {
+		return PictureURL{}, ErrPictureTooLarge
+	}
 
-	URL := httpReq.URL
-	query := URL.Query()
-	query.Set("token", c.authorizationToken)
-	URL.RawQuery = query.Encode()
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && pictureContentTypes[mediaType] {
+		contentType = mediaType
+	} else {
+		contentType = detectPictureType(picture)
+	}
+	if !pictureContentTypes[contentType] {
+		return PictureURL{}, fmt.Errorf("%w: %s", ErrUnsupportedPicture, contentType)
+	}
+
+	return c.uploadPicture(ctx, picture, contentType)
+}
+
+// UploadPictureBytes posts the picture to the GroupMe image service as is,
+// detecting its Content-Type. See UploadPictureReader
+func (c *client) UploadPictureBytes(ctx context.Context, picture []byte) (PictureURL, error) {
+	return c.UploadPictureReader(ctx, bytes.NewReader(picture), "")
+}
+
+// UploadPictureFile posts the picture file at path to the GroupMe image service
+// as is, detecting its Content-Type. See UploadPictureReader
+func (c *client) UploadPictureFile(ctx context.Context, path string) (PictureURL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return PictureURL{}, err
+	}
+	defer f.Close()
+
+	picture, err := c.UploadPictureReader(ctx, f, "")
+	if err != nil {
+		return PictureURL{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return picture, nil
+}
+
+/*
+UploadPictureURL -
+
+Downloads the picture at the URL and re-hosts it on the GroupMe image
+service as is, so it can be used in messages and avatars. At most
+MaxPictureSize bytes are downloaded. See UploadPictureReader
+*/
+func (c *client) UploadPictureURL(ctx context.Context, pictureURL string) (PictureURL, error) {
+	// Don't download a picture that can't be uploaded
+	if c.imageToken == "" {
+		return PictureURL{}, ErrNoImageToken
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, pictureURL, nil)
+	if err != nil {
+		return PictureURL{}, err
+	}
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -68,7 +166,39 @@ func (c *Client) UploadPicture(ctx context.Context, img image.Image, encoding Pi
 	}
 	defer httpResp.Body.Close()
 
-	decoder := json.NewDecoder(httpResp.Body)
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= errorStatusCodeMin {
+		return PictureURL{}, fmt.Errorf("downloading %s: %s", pictureURL, httpResp.Status)
+	}
+
+	return c.UploadPictureReader(ctx, httpResp.Body, httpResp.Header.Get("Content-Type"))
+}
+
+// detectPictureType returns the Content-Type of the start of a picture
+func detectPictureType(head []byte) string {
+	// Not detected by http.DetectContentType
+	if len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WEBP" {
+		return "image/webp"
+	}
+	return http.DetectContentType(head)
+}
+
+// uploadPicture posts the picture to the image service. Failures are
+// returned as an *APIError, like those of the API
+func (c *client) uploadPicture(ctx context.Context, picture []byte, contentType string) (PictureURL, error) {
+	if c.imageToken == "" {
+		return PictureURL{}, ErrNoImageToken
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, c.imageEndpointBase+uploadPictureEndpoint, bytes.NewReader(picture))
+	if err != nil {
+		return PictureURL{}, err
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+
+	URL := httpReq.URL
+	query := URL.Query()
+	query.Set("token", c.imageToken)
+	URL.RawQuery = query.Encode()
 
 	var resp struct {
 		Payload struct {
@@ -76,9 +206,15 @@ func (c *Client) UploadPicture(ctx context.Context, img image.Image, encoding Pi
 			PictureURL string `json:"picture_url"`
 		} `json:"payload"`
 	}
-	if err := decoder.Decode(&resp); err != nil {
+
+	// A repeated upload only stores the picture again, so it is safe to retry
+	ctx = withIdempotency(ctx)
+	if err := c.do(ctx, httpReq, unwrappedResponse{&resp}); err != nil {
 		return PictureURL{}, err
 	}
+	if resp.Payload.URL == "" {
+		return PictureURL{}, ErrNoPictureURL
+	}
 
 	return PictureURL{
 		Base:    resp.Payload.URL,
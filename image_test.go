@@ -4,12 +4,22 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
 	"image/jpeg"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
 	"testing"
 
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -21,6 +31,8 @@ type PictureAPISuite struct{ APISuite }
 func (s *PictureAPISuite) SetupSuite() {
 	s.handler = picturesTestRouter()
 	s.setupSuite()
+	// Uploads need an image token
+	s.client.imageToken = "token"
 }
 
 func (s *PictureAPISuite) TestUsersMe() {
@@ -49,11 +61,164 @@ func picturesTestRouter() *mux.Router {
 			fmt.Fprint(w, `{
 				"payload": {
 					"url": "https://test.com/100x100.jpeg.123456789",
-					"picture_url": "https://test.com/100x100.jpeg.123456789",
-				},
+					"picture_url": "https://test.com/100x100.jpeg.123456789"
+				}
 			}`)
 		})
 
 	/*// Return test router //*/
 	return router
 }
+
+// pictureServer records the pictures uploaded to it
+func pictureServer(t *testing.T) (*httptest.Server, *[]*http.Request, *[][]byte) {
+	var requests []*http.Request
+	var bodies [][]byte
+	// Encoded here, as the handler can't stop the test on failure
+	remote := animatedGIF(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		requests = append(requests, req)
+		bodies = append(bodies, body)
+
+		switch req.URL.Path {
+		case "/pictures":
+			if req.URL.Query().Get("token") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"errors": ["unauthorized"]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"payload": {"url": "https://i.groupme.com/%d", "picture_url": "https://i.groupme.com/%d"}}`, len(bodies), len(bodies))
+		case "/remote.gif":
+			w.Header().Set("Content-Type", "image/gif; charset=binary")
+			w.Write(remote)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	return server, &requests, &bodies
+}
+
+func animatedGIF(t *testing.T) []byte {
+	palette := color.Palette{color.Black, color.White}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, &gif.GIF{
+		Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 2, 2), palette), image.NewPaletted(image.Rect(0, 0, 2, 2), palette)},
+		Delay: []int{10, 10},
+	}))
+	return buf.Bytes()
+}
+
+func TestUploadPictureReader(t *testing.T) {
+	server, requests, bodies := pictureServer(t)
+	defer server.Close()
+	ctx := context.Background()
+	client := NewClient("token", WithImageBaseURL(server.URL))
+
+	animated := animatedGIF(t)
+	jpegReader := bytes.NewReader(imgBytes)
+	webp := []byte("RIFF\x1a\x00\x00\x00WEBPVP8L\x0d\x00\x00\x00/\x00\x00\x00\x10\x07\x10\x11\x11\x88\x88\xfe\x07\x00")
+	uploads := []struct {
+		upload      func() (PictureURL, error)
+		contentType string
+		body        []byte
+	}{
+		{func() (PictureURL, error) { return client.UploadPictureReader(ctx, bytes.NewReader(animated), "") }, "image/gif", animated},
+		{func() (PictureURL, error) { return client.UploadPictureBytes(ctx, webp) }, "image/webp", webp},
+		{func() (PictureURL, error) { return client.UploadPictureReader(ctx, jpegReader, "image/jpeg") }, "image/jpeg", imgBytes},
+		{func() (PictureURL, error) { return client.UploadPictureFile(ctx, "image.jpeg") }, "image/jpeg", imgBytes},
+	}
+	for i, upload := range uploads {
+		picture, err := upload.upload()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("https://i.groupme.com/%d", i+1), picture.Base)
+
+		req := (*requests)[i]
+		assert.Equal(t, upload.contentType, req.Header.Get("Content-Type"))
+		assert.Equal(t, "token", req.URL.Query().Get("token"))
+		assert.Equal(t, upload.body, (*bodies)[i], "the picture is uploaded as is")
+	}
+
+	_, err := client.UploadPictureBytes(ctx, []byte("not a picture"))
+	assert.ErrorIs(t, err, ErrUnsupportedPicture)
+	_, err = client.UploadPictureReader(ctx, bytes.NewReader(make([]byte, MaxPictureSize+1)), "image/png")
+	assert.ErrorIs(t, err, ErrPictureTooLarge)
+	_, err = client.UploadPictureFile(ctx, "missing.png")
+	assert.True(t, os.IsNotExist(err))
+	assert.Len(t, *requests, len(uploads))
+}
+
+func TestUploadPictureURL(t *testing.T) {
+	server, requests, bodies := pictureServer(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	// Bots upload with a user's token
+	botClient := NewBotClient("bot-id", WithImageBaseURL(server.URL))
+	_, err := botClient.UploadPictureURL(ctx, server.URL+"/remote.gif")
+	assert.ErrorIs(t, err, ErrNoImageToken)
+	_, err = botClient.UploadPictureBytes(ctx, imgBytes)
+	assert.ErrorIs(t, err, ErrNoImageToken)
+	assert.Empty(t, *requests)
+
+	botClient = NewBotClient("bot-id", WithImageBaseURL(server.URL), WithImageToken("token"))
+	picture, err := botClient.UploadPictureURL(ctx, server.URL+"/remote.gif")
+	require.NoError(t, err)
+	assert.NotEmpty(t, picture.Base)
+
+	upload := (*requests)[len(*requests)-1]
+	assert.Equal(t, "/pictures", upload.URL.Path)
+	assert.Equal(t, "image/gif", upload.Header.Get("Content-Type"))
+	assert.Equal(t, animatedGIF(t), (*bodies)[len(*bodies)-1])
+
+	_, err = botClient.UploadPictureURL(ctx, server.URL+"/missing.png")
+	assert.Error(t, err)
+}
+
+func TestUploadPicture_Errors(t *testing.T) {
+	responses := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusServiceUnavailable, `<html>Service Unavailable</html>`},
+		{http.StatusOK, `{"payload": {"url": "https://i.groupme.com/1"}}`},
+		{http.StatusOK, `{"payload": {}}`},
+		{http.StatusRequestEntityTooLarge, `{"errors": ["too large"]}`},
+	}
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		assert.Equal(t, imgBytes, body, "retries send the whole picture")
+
+		response := responses[atomic.AddInt32(&calls, 1)-1]
+		w.WriteHeader(response.status)
+		fmt.Fprint(w, response.body)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	client := NewClient("secret", WithImageBaseURL(server.URL), WithRetryPolicy(testRetryPolicy()))
+
+	// The server error is retried
+	picture, err := client.UploadPictureBytes(ctx, imgBytes)
+	require.NoError(t, err)
+	assert.Equal(t, "https://i.groupme.com/1", picture.Base)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	_, err = client.UploadPictureBytes(ctx, imgBytes)
+	assert.ErrorIs(t, err, ErrNoPictureURL)
+
+	_, err = client.UploadPictureBytes(ctx, imgBytes)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, apiErr.StatusCode)
+	assert.Equal(t, "/pictures?token=REDACTED", apiErr.Endpoint)
+	assert.NotContains(t, err.Error(), "secret")
+}
@@ -141,9 +141,16 @@ const (
 	Image    attachmentType = "image"
 	Location attachmentType = "location"
 	Emoji    attachmentType = "emoji"
+	Reply    attachmentType = "reply"
+	Split    attachmentType = "split"
+	File     attachmentType = "file"
+	Video    attachmentType = "video"
+	Poll     attachmentType = "poll"
+	Event    attachmentType = "event"
 )
 
-// Attachment is a GroupMe message attachment, returned in JSON API responses
+// Attachment is a GroupMe message attachment, returned in JSON API responses.
+// Typed returns the fields relevant to its Type
 type Attachment struct {
 	Type        attachmentType `json:"type,omitempty"`
 	Loci        [][]int        `json:"loci,omitempty"`
@@ -154,6 +161,18 @@ type Attachment struct {
 	Longitude   string         `json:"lng,omitempty"`
 	Placeholder string         `json:"placeholder,omitempty"`
 	Charmap     [][]int        `json:"charmap,omitempty"`
+	ReplyID     string         `json:"reply_id,omitempty"`
+	BaseReplyID string         `json:"base_reply_id,omitempty"`
+	UserID      string         `json:"user_id,omitempty"`
+	Token       string         `json:"token,omitempty"`
+	FileID      string         `json:"file_id,omitempty"`
+	PreviewURL  string         `json:"preview_url,omitempty"`
+	PollID      string         `json:"poll_id,omitempty"`
+	EventID     string         `json:"event_id,omitempty"`
+	View        string         `json:"view,omitempty"`
+	// Raw is the attachment JSON as received, preserving
+	// the fields of attachment types without typed fields
+	Raw json.RawMessage `json:"-"`
 }
 
 func (a *Attachment) String() string {
@@ -1,13 +1,11 @@
 package groupme
 
 import (
-	"fmt"
-	"log"
-	"math/rand"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
-	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -18,8 +16,7 @@ type APISuite struct {
 	// Base attributes
 	suite.Suite
 	client *Client
-	server *http.Server
-	wg     sync.WaitGroup
+	server *httptest.Server
 
 	// Overridden by child Suite
 	addr    string
@@ -27,56 +24,17 @@ type APISuite struct {
 }
 
 func (s *APISuite) setupSuite() {
-	s.addr = "localhost:" + GeneratePort()
+	s.server = httptest.NewServer(s.handler)
+	s.addr = "localhost:" + strconv.Itoa(s.server.Listener.Addr().(*net.TCPAddr).Port)
 
 	s.client = NewClient("")
 	s.client.apiEndpointBase = "http://" + s.addr
-
-	s.server = s.startServer(s.addr, s.handler)
+	s.client.imageEndpointBase = "http://" + s.addr
 }
 
 func (s *APISuite) TearDownSuite() {
 	s.client.Close()
 	s.server.Close()
-	s.wg.Wait()
-}
-
-/*/// Start Server ///*/
-func (s *APISuite) startServer(addr string, handler http.Handler) *http.Server {
-	server := &http.Server{
-		Addr:     addr,
-		Handler:  handler,
-		ErrorLog: log.New(os.Stdout, "SERVER", log.Ltime),
-	}
-
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		if err := server.ListenAndServe(); err.Error() != "http: Server closed" {
-			s.Assert().NoError(err)
-		}
-	}()
-
-	// Wait until server has started listening
-	url := fmt.Sprintf("http://%s", addr)
-	// nolint // url is meant to be variable
-	for _, err := http.Get(url); err != nil; _, err = http.Get(url) {
-		continue
-	}
-
-	return server
-}
-
-/*/// Generate Ephemeral Port ///*/
-const (
-	portMin   = 49152
-	portMax   = 65535
-	portRange = portMax - portMin
-)
-
-func GeneratePort() string {
-	// nolint // weak random generator is ok for creating port number in a test
-	return strconv.Itoa((rand.Intn(portRange) + portMin))
 }
 
 /*//////// Test Main ////////*/
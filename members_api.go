@@ -49,6 +49,10 @@ Parameters:
 			Email - string
 */
 func (c *Client) AddMembers(ctx context.Context, groupID string, members ...*Member) (string, error) {
+	if err := c.validate(validID("group_id", groupID), validMembers(members)); err != nil {
+		return "", err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase+addMembersEndpoint, groupID)
 
 	var data = struct {
@@ -152,6 +156,10 @@ Update your nickname in a group. The nickname must be
 between 1 and 50 characters.
 */
 func (c *Client) UpdateMember(ctx context.Context, groupID string, nickname string) (*Member, error) {
+	if err := c.validate(validID("group_id", groupID), validNickname(nickname)); err != nil {
+		return nil, err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase+updateMemberEndpoint, groupID)
 
 	type Nickname struct {
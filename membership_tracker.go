@@ -0,0 +1,294 @@
+package groupme
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+type memberEventType string
+
+// Member event types
+const (
+	MemberJoined    memberEventType = "member.joined"
+	MemberLeft      memberEventType = "member.left"
+	NicknameChanged memberEventType = "member.nickname_changed"
+	AvatarChanged   memberEventType = "member.avatar_changed"
+	MuteChanged     memberEventType = "member.mute_changed"
+)
+
+// MemberEvent is a change to a group's members, detected by a MembershipTracker
+type MemberEvent struct {
+	Type    memberEventType
+	GroupID string
+	// Member after the change. The member before leaving for MemberLeft
+	Member *Member
+	// Member before the change. Nil for MemberJoined and MemberLeft
+	Previous *Member
+	// The system message announcing the change, if one was found.
+	// GroupMe announces joins, departures and nickname changes
+	Message *Message
+}
+
+func (e MemberEvent) String() string {
+	return marshal(&e)
+}
+
+// diffMembers returns the events changing the previous members into the current,
+// departures first, then joins and changes in the order of the current members
+func diffMembers(groupID string, previous, current []*Member) []*MemberEvent {
+	byUserID := make(map[string]*Member, len(previous))
+	for _, member := range previous {
+		byUserID[member.UserID] = member
+	}
+	currentIDs := make(map[string]bool, len(current))
+	for _, member := range current {
+		currentIDs[member.UserID] = true
+	}
+
+	var events []*MemberEvent
+	for _, member := range previous {
+		if !currentIDs[member.UserID] {
+			events = append(events, &MemberEvent{Type: MemberLeft, GroupID: groupID, Member: member})
+		}
+	}
+
+	for _, member := range current {
+		before, ok := byUserID[member.UserID]
+		if !ok {
+			events = append(events, &MemberEvent{Type: MemberJoined, GroupID: groupID, Member: member})
+			continue
+		}
+
+		if member.Nickname != before.Nickname {
+			events = append(events, &MemberEvent{Type: NicknameChanged, GroupID: groupID, Member: member, Previous: before})
+		}
+		if member.ImageURL != before.ImageURL {
+			events = append(events, &MemberEvent{Type: AvatarChanged, GroupID: groupID, Member: member, Previous: before})
+		}
+		if member.Muted != before.Muted {
+			events = append(events, &MemberEvent{Type: MuteChanged, GroupID: groupID, Member: member, Previous: before})
+		}
+	}
+	return events
+}
+
+// announces reports whether the system message text announces the event
+func announces(text string, event *MemberEvent) bool {
+	switch event.Type {
+	case MemberJoined:
+		return strings.Contains(text, event.Member.Nickname) &&
+			(strings.Contains(text, " added ") || strings.Contains(text, " joined ") || strings.Contains(text, " rejoined "))
+	case MemberLeft:
+		return strings.Contains(text, event.Member.Nickname) &&
+			(strings.Contains(text, " left ") || strings.Contains(text, " removed "))
+	case NicknameChanged:
+		return strings.Contains(text, event.Previous.Nickname+" changed name to "+event.Member.Nickname)
+	}
+	return false
+}
+
+/*//////// Membership Tracker ////////*/
+
+// membersSnapshot is the state a MembershipTracker keeps for each group
+type membersSnapshot struct {
+	Members []*Member `json:"members"`
+	// LastMessageID is the group's last message when the snapshot was taken
+	LastMessageID string `json:"last_message_id"`
+}
+
+// MembershipTracker polls groups with ShowGroup, delivering an event for
+// every member who joined, left, or changed their nickname, avatar or mute
+// setting since the previous poll.
+//
+//	tracker := client.NewMembershipTracker([]string{groupID})
+//	go tracker.Run(ctx)
+//	for event := range tracker.Events() {
+//		...
+//	}
+type MembershipTracker struct {
+	client       *Client
+	groupIDs     []string
+	interval     time.Duration
+	store        Store
+	storePrefix  string
+	errorHandler func(groupID string, err error)
+	events       chan *MemberEvent
+}
+
+// MembershipTrackerOption configures a MembershipTracker
+type MembershipTrackerOption func(*MembershipTracker)
+
+// WithTrackInterval sets the interval between polls. Defaults to 30 seconds.
+// Intervals of zero or less are ignored
+func WithTrackInterval(interval time.Duration) MembershipTrackerOption {
+	return func(t *MembershipTracker) {
+		if interval > 0 {
+			t.interval = interval
+		}
+	}
+}
+
+// WithSnapshotStore persists the snapshots in the store, under the key prefix
+// followed by the group ID, so a restarted MembershipTracker reports the
+// changes made while it was stopped. Defaults to a MemoryStore
+func WithSnapshotStore(store Store, prefix string) MembershipTrackerOption {
+	return func(t *MembershipTracker) {
+		t.store = store
+		t.storePrefix = prefix
+	}
+}
+
+// WithTrackErrorHandler sets the function called with API errors, which are
+// retried on the next poll
+func WithTrackErrorHandler(handler func(groupID string, err error)) MembershipTrackerOption {
+	return func(t *MembershipTracker) {
+		t.errorHandler = handler
+	}
+}
+
+// WithTrackBuffer sets how many events are buffered before polling
+// waits for the receiver. Defaults to 0
+func WithTrackBuffer(size int) MembershipTrackerOption {
+	return func(t *MembershipTracker) {
+		t.events = make(chan *MemberEvent, size)
+	}
+}
+
+// NewMembershipTracker creates a MembershipTracker of the groups
+func (c *Client) NewMembershipTracker(groupIDs []string, options ...MembershipTrackerOption) *MembershipTracker {
+	t := &MembershipTracker{
+		client:       c,
+		groupIDs:     groupIDs,
+		interval:     30 * time.Second,
+		errorHandler: func(string, error) {},
+		store:        NewMemoryStore(),
+		events:       make(chan *MemberEvent),
+	}
+
+	for _, option := range options {
+		option(t)
+	}
+
+	return t
+}
+
+// Events returns the channel member events are delivered on.
+// It is closed when Run returns
+func (t *MembershipTracker) Events() <-chan *MemberEvent {
+	return t.events
+}
+
+// Run polls until the context is done, returning its error, or until a
+// snapshot can't be loaded or saved. The first snapshot of a group is
+// taken without delivering events
+func (t *MembershipTracker) Run(ctx context.Context) error {
+	defer close(t.events)
+
+	for {
+		for _, groupID := range t.groupIDs {
+			if err := t.poll(ctx, groupID); err != nil {
+				return err
+			}
+		}
+
+		timer := time.NewTimer(t.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// poll snapshots the group and delivers the changes since the previous
+// snapshot. Only context and store errors are returned
+func (t *MembershipTracker) poll(ctx context.Context, groupID string) error {
+	previous, err := t.snapshot(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	group, err := t.client.ShowGroup(ctx, groupID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		t.errorHandler(groupID, err)
+		return nil
+	}
+	current := &membersSnapshot{Members: group.Members, LastMessageID: group.Messages.LastMessageID}
+
+	if previous != nil {
+		events := diffMembers(groupID, previous.Members, current.Members)
+		// Without a last message, the whole history would have to be searched
+		if len(events) > 0 && previous.LastMessageID != "" {
+			t.correlate(ctx, groupID, previous.LastMessageID, events)
+		}
+
+		for _, event := range events {
+			select {
+			case t.events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return t.setSnapshot(ctx, groupID, current)
+}
+
+// correlate finds the system messages after the message ID announcing the events.
+// Events of groups without a message at the previous snapshot aren't correlated
+func (t *MembershipTracker) correlate(ctx context.Context, groupID, lastMessageID string, events []*MemberEvent) {
+	var system []*Message
+	iter := t.client.IterateMessages(groupID, &MessageIteratorOptions{
+		Direction: IterateForward,
+		StartID:   lastMessageID,
+	})
+	for iter.Next(ctx) {
+		if msg := iter.Message(); msg.System || msg.SenderType == SenderTypeSystem {
+			system = append(system, msg)
+		}
+	}
+	if err := iter.Err(); err != nil && ctx.Err() == nil {
+		t.errorHandler(groupID, err)
+	}
+
+	for _, event := range events {
+		// The latest announcement matches the latest change
+		for i := len(system) - 1; i >= 0; i-- {
+			if announces(system[i].Text, event) {
+				event.Message = system[i]
+				break
+			}
+		}
+	}
+}
+
+func (t *MembershipTracker) snapshot(ctx context.Context, groupID string) (*membersSnapshot, error) {
+	data, err := t.store.Get(ctx, t.storePrefix+groupID)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot membersSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (t *MembershipTracker) setSnapshot(ctx context.Context, groupID string, snapshot *membersSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return t.store.Put(ctx, t.storePrefix+groupID, data)
+}
@@ -0,0 +1,166 @@
+package groupme_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receiveMemberEvent(t *testing.T, tracker *groupme.MembershipTracker) *groupme.MemberEvent {
+	t.Helper()
+	select {
+	case event := <-tracker.Events():
+		require.NotNil(t, event)
+		return event
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "no member event")
+		return nil
+	}
+}
+
+func TestMembershipTracker(t *testing.T) {
+	team := newTeam(t, "2")
+	alice, bob, group := team.alice, team.client(t, "2"), team.group
+	team.server.SystemMessages = true
+	team.server.AddMessage(group.ID, "1", "Hello")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var errs []error
+	store := groupme.NewMemoryStore()
+	tracker := alice.NewMembershipTracker([]string{group.ID},
+		groupme.WithTrackInterval(10*time.Millisecond),
+		groupme.WithSnapshotStore(store, ""),
+		groupme.WithTrackErrorHandler(func(groupID string, err error) { errs = append(errs, err) }),
+	)
+	done := make(chan error, 1)
+	go func() { done <- tracker.Run(ctx) }()
+
+	// Changes are only reported after the first snapshot
+	require.Eventually(t, func() bool {
+		_, err := store.Get(ctx, group.ID)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := alice.AddMembers(ctx, group.ID, &groupme.Member{Nickname: "Carol", UserID: "3"})
+	require.NoError(t, err)
+	event := receiveMemberEvent(t, tracker)
+	assert.Equal(t, groupme.MemberJoined, event.Type)
+	assert.Equal(t, group.ID, event.GroupID)
+	assert.Equal(t, "3", event.Member.UserID)
+	require.NotNil(t, event.Message)
+	assert.Equal(t, "Alice added Carol to the group.", event.Message.Text)
+
+	_, err = bob.UpdateMember(ctx, group.ID, "Bobby")
+	require.NoError(t, err)
+	event = receiveMemberEvent(t, tracker)
+	assert.Equal(t, groupme.NicknameChanged, event.Type)
+	assert.Equal(t, "Bob", event.Previous.Nickname)
+	assert.Equal(t, "Bobby", event.Member.Nickname)
+	require.NotNil(t, event.Message)
+	assert.Equal(t, "Bob changed name to Bobby", event.Message.Text)
+
+	membership := team.server.Group(group.ID).GetMemberByUserID("2")
+	require.NoError(t, bob.RemoveMember(ctx, group.ID, membership.ID))
+	event = receiveMemberEvent(t, tracker)
+	assert.Equal(t, groupme.MemberLeft, event.Type)
+	assert.Equal(t, "Bobby", event.Member.Nickname)
+	require.NotNil(t, event.Message)
+	assert.Equal(t, "Bobby has left the group.", event.Message.Text)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Empty(t, errs)
+}
+
+func TestMembershipTracker_SnapshotStore(t *testing.T) {
+	team := newTeam(t)
+	group := team.group
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The snapshot of a previous run, before Alice changed her avatar and Bob left
+	snapshot, err := json.Marshal(map[string]interface{}{
+		"members": []*groupme.Member{
+			{ID: "a", UserID: "1", Nickname: "Alice", ImageURL: "https://i.groupme.com/old", Muted: true},
+			{ID: "b", UserID: "2", Nickname: "Bob"},
+		},
+	})
+	require.NoError(t, err)
+	store := groupme.NewMemoryStore()
+	require.NoError(t, store.Put(ctx, "members/"+group.ID, snapshot))
+
+	tracker := team.alice.NewMembershipTracker([]string{group.ID}, groupme.WithSnapshotStore(store, "members/"))
+	go func() { _ = tracker.Run(ctx) }()
+
+	var types []interface{}
+	for i := 0; i < 3; i++ {
+		types = append(types, receiveMemberEvent(t, tracker).Type)
+	}
+	assert.Equal(t, []interface{}{groupme.MemberLeft, groupme.AvatarChanged, groupme.MuteChanged}, types)
+
+	require.Eventually(t, func() bool {
+		data, err := store.Get(ctx, "members/"+group.ID)
+		return err == nil && string(data) != string(snapshot)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMembershipTracker_NoLastMessage(t *testing.T) {
+	team := newTeam(t)
+	team.server.SystemMessages = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := groupme.NewMemoryStore()
+	tracker := team.alice.NewMembershipTracker([]string{team.group.ID},
+		groupme.WithTrackInterval(10*time.Millisecond),
+		groupme.WithSnapshotStore(store, ""),
+	)
+	go func() { _ = tracker.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		_, err := store.Get(ctx, team.group.ID)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// The group had no messages at the first snapshot, so its
+	// history isn't searched for the announcement
+	_, err := team.alice.AddMembers(ctx, team.group.ID, &groupme.Member{Nickname: "Bob", UserID: "2"})
+	require.NoError(t, err)
+	event := receiveMemberEvent(t, tracker)
+	assert.Equal(t, groupme.MemberJoined, event.Type)
+	assert.Nil(t, event.Message)
+}
+
+// countingTransport counts the requests it sends
+type countingTransport struct {
+	requests int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestMembershipTracker_NonPositiveInterval(t *testing.T) {
+	team := newTeam(t)
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		var transport countingTransport
+		client := groupme.NewClient(team.tokens["1"], append(team.server.ClientOptions(), groupme.WithHTTPClient(&http.Client{Transport: &transport}))...)
+		defer client.Close()
+
+		// The interval is ignored, so the default interval follows the first poll
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		tracker := client.NewMembershipTracker([]string{team.group.ID}, groupme.WithTrackInterval(interval))
+		assert.ErrorIs(t, tracker.Run(ctx), context.DeadlineExceeded)
+		cancel()
+		assert.EqualValues(t, 1, atomic.LoadInt32(&transport.requests), "polls with interval %s", interval)
+	}
+}
@@ -0,0 +1,128 @@
+package groupme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// MessageBuilder builds message text containing mentions, computing the
+// loci of each mention in the mentions attachment:
+//
+//	msg, err := groupme.NewMessageBuilder(group).
+//		Text("Hey ").Mention(member).Text(", lunch?").
+//		Build()
+//
+// Loci are offsets and lengths in UTF-16 code units, as used by GroupMe clients
+type MessageBuilder struct {
+	group   *Group
+	text    strings.Builder
+	length  int
+	loci    [][]int
+	userIDs []string
+	err     error
+}
+
+// NewMessageBuilder creates a MessageBuilder. The group resolves nicknames
+// for MentionNickname and members for MentionAll, and may be nil otherwise
+func NewMessageBuilder(group *Group) *MessageBuilder {
+	return &MessageBuilder{group: group}
+}
+
+// Text appends plain text
+func (b *MessageBuilder) Text(text string) *MessageBuilder {
+	b.text.WriteString(text)
+	b.length += utf16Len(text)
+	return b
+}
+
+// Mention appends "@" and the member's nickname, mentioning the member
+func (b *MessageBuilder) Mention(member *Member) *MessageBuilder {
+	if member == nil || member.UserID == "" || member.Nickname == "" {
+		b.setErr(errors.New("mentioned member must have a user ID and nickname"))
+		return b
+	}
+
+	b.mention("@"+member.Nickname, member.UserID)
+	return b
+}
+
+// MentionNickname mentions the group member with the nickname
+func (b *MessageBuilder) MentionNickname(nickname string) *MessageBuilder {
+	if b.group == nil {
+		b.setErr(fmt.Errorf("no group to find @%s in", nickname))
+		return b
+	}
+
+	member := b.group.GetMemberByNickname(nickname)
+	if member == nil {
+		b.setErr(fmt.Errorf("no member of group %s has nickname %s", b.group.ID, nickname))
+		return b
+	}
+	return b.Mention(member)
+}
+
+// MentionAll appends "@all", mentioning every group member except the excluded users,
+// typically the sender
+func (b *MessageBuilder) MentionAll(excludeUserIDs ...string) *MessageBuilder {
+	if b.group == nil {
+		b.setErr(errors.New("no group to mention @all in"))
+		return b
+	}
+
+	excluded := map[string]bool{}
+	for _, userID := range excludeUserIDs {
+		excluded[userID] = true
+	}
+
+	var userIDs []string
+	for _, member := range b.group.Members {
+		if !excluded[member.UserID] {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	b.mention("@all", userIDs...)
+	return b
+}
+
+// mention appends the text, with a locus covering it for each user
+func (b *MessageBuilder) mention(text string, userIDs ...string) {
+	start := b.length
+	b.Text(text)
+
+	for _, userID := range userIDs {
+		b.loci = append(b.loci, []int{start, b.length - start})
+		b.userIDs = append(b.userIDs, userID)
+	}
+}
+
+func (b *MessageBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Build returns the message, with a mentions attachment if anyone was mentioned,
+// or the first error encountered while building
+func (b *MessageBuilder) Build() (*Message, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	msg := &Message{Text: b.text.String()}
+	if len(b.userIDs) > 0 {
+		msg.Attachments = []*Attachment{{
+			Type:    Mentions,
+			Loci:    b.loci,
+			UserIDs: b.userIDs,
+		}}
+	}
+	return msg, nil
+}
+
+// utf16Len returns the length of the text in UTF-16 code units
+func utf16Len(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
@@ -0,0 +1,47 @@
+package groupme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBuilder(t *testing.T) {
+	group := &Group{
+		ID: "1",
+		Members: []*Member{
+			{UserID: "10", Nickname: "Alice"},
+			{UserID: "20", Nickname: "Bob 🎉"},
+		},
+	}
+
+	msg, err := NewMessageBuilder(group).
+		Text("🍕 Hey ").Mention(group.Members[0]).
+		Text(" and ").MentionNickname("Bob 🎉").
+		Text(", lunch? ").MentionAll("10").
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "🍕 Hey @Alice and @Bob 🎉, lunch? @all", msg.Text)
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, Mentions, msg.Attachments[0].Type)
+	// The emoji are two UTF-16 code units each
+	assert.Equal(t, [][]int{{7, 6}, {18, 7}, {34, 4}}, msg.Attachments[0].Loci)
+	assert.Equal(t, []string{"10", "20", "20"}, msg.Attachments[0].UserIDs)
+}
+
+func TestMessageBuilder_Errors(t *testing.T) {
+	msg, err := NewMessageBuilder(nil).Text("No mentions").Build()
+	require.NoError(t, err)
+	assert.Empty(t, msg.Attachments)
+
+	_, err = NewMessageBuilder(&Group{}).MentionNickname("Nobody").Build()
+	assert.Error(t, err)
+
+	_, err = NewMessageBuilder(nil).MentionAll().Build()
+	assert.Error(t, err)
+
+	_, err = NewMessageBuilder(nil).Mention(&Member{Nickname: "No ID"}).Build()
+	assert.Error(t, err)
+}
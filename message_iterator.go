@@ -0,0 +1,212 @@
+package groupme
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+type iterationDirection int
+
+// Iteration directions
+const (
+	// Newest to oldest, paging with before_id
+	IterateBackward iterationDirection = iota
+	// Oldest to newest, paging with after_id
+	IterateForward
+)
+
+// maxMessagesLimit is the largest page IndexMessages returns
+const maxMessagesLimit = 100
+
+// MessageIteratorOptions defines where a MessageIterator starts and stops
+type MessageIteratorOptions struct {
+	// Defaults to IterateBackward
+	Direction iterationDirection
+	// Message ID to start after (exclusive). Defaults to the newest
+	// message when iterating backward, and the oldest when iterating forward
+	StartID string
+	// Message ID to stop at (exclusive)
+	StopID string
+	// Only messages created at or after this time are returned
+	After Timestamp
+	// Only messages created at or before this time are returned
+	Before Timestamp
+	// Number of messages requested per page. Defaults to (and at most) 100.
+	// Direct message pages are always 20 messages
+	PageSize int
+}
+
+// pageFunc fetches the page of messages before, or after, the cursor
+type pageFunc func(ctx context.Context, direction iterationDirection, cursor string, limit int) ([]*Message, error)
+
+// MessageIterator pages through the messages of a group or direct message
+// conversation, one message at a time.
+//
+//	iter := client.IterateMessages(groupID, nil)
+//	for iter.Next(ctx) {
+//		fmt.Println(iter.Message().Text)
+//	}
+//	if err := iter.Err(); err != nil {
+//		...
+//	}
+type MessageIterator struct {
+	fetchPage pageFunc
+	options   MessageIteratorOptions
+
+	cursor  string
+	page    []*Message
+	current *Message
+	done    bool
+	err     error
+}
+
+// IterateMessages creates an iterator over a group's entire history,
+// or the part of it bounded by the options
+func (c *Client) IterateMessages(groupID string, options *MessageIteratorOptions) *MessageIterator {
+	return newMessageIterator(func(ctx context.Context, direction iterationDirection, cursor string, limit int) ([]*Message, error) {
+		query := &IndexMessagesQuery{Limit: limit}
+		if direction == IterateForward {
+			query.AfterID = cursor
+		} else {
+			query.BeforeID = cursor
+		}
+
+		resp, err := c.IndexMessages(ctx, groupID, query)
+		return resp.Messages, err
+	}, options)
+}
+
+// IterateDirectMessages creates an iterator over the entire history of the
+// direct message conversation with the other user, or the part of it bounded
+// by the options. Direct messages can only be iterated backward, as
+// IndexDirectMessages has no equivalent of after_id
+func (c *Client) IterateDirectMessages(otherUserID string, options *MessageIteratorOptions) *MessageIterator {
+	iter := newMessageIterator(func(ctx context.Context, direction iterationDirection, cursor string, limit int) ([]*Message, error) {
+		resp, err := c.IndexDirectMessages(ctx, otherUserID, &IndexDirectMessagesQuery{BeforeID: cursor})
+		return resp.Messages, err
+	}, options)
+
+	if iter.options.Direction == IterateForward {
+		iter.finish(errors.New("direct messages can only be iterated backward"))
+	}
+	return iter
+}
+
+func newMessageIterator(fetchPage pageFunc, options *MessageIteratorOptions) *MessageIterator {
+	iter := &MessageIterator{fetchPage: fetchPage}
+	if options != nil {
+		iter.options = *options
+	}
+	if iter.options.PageSize <= 0 || iter.options.PageSize > maxMessagesLimit {
+		iter.options.PageSize = maxMessagesLimit
+	}
+
+	iter.cursor = iter.options.StartID
+	if iter.cursor == "" && iter.options.Direction == IterateForward {
+		// Every message comes after 0
+		iter.cursor = "0"
+	}
+
+	return iter
+}
+
+// Next advances to the next message, fetching another page when needed.
+// It returns false when the iteration is complete or an error occurred
+func (it *MessageIterator) Next(ctx context.Context) bool {
+	for !it.done {
+		for len(it.page) > 0 {
+			msg := it.page[0]
+			it.page = it.page[1:]
+			it.cursor = msg.ID
+
+			if it.stopped(msg) {
+				it.finish(nil)
+				return false
+			}
+			if it.skipped(msg) {
+				continue
+			}
+
+			it.current = msg
+			return true
+		}
+
+		it.fetch(ctx)
+	}
+
+	return false
+}
+
+// Message returns the current message
+func (it *MessageIterator) Message() *Message {
+	return it.current
+}
+
+// Err returns the error that ended the iteration, if any
+func (it *MessageIterator) Err() error {
+	return it.err
+}
+
+func (it *MessageIterator) finish(err error) {
+	it.done = true
+	it.current = nil
+	it.page = nil
+	it.err = err
+}
+
+func (it *MessageIterator) fetch(ctx context.Context) {
+	page, err := it.fetchPage(ctx, it.options.Direction, it.cursor, it.options.PageSize)
+	if errors.Is(err, ErrNotModified) {
+		// No more messages
+		it.finish(nil)
+		return
+	}
+	if err != nil {
+		it.finish(err)
+		return
+	}
+	if len(page) == 0 {
+		it.finish(nil)
+		return
+	}
+
+	it.page = page
+}
+
+// stopped reports whether msg is past the end of the iteration
+func (it *MessageIterator) stopped(msg *Message) bool {
+	if it.options.Direction == IterateForward {
+		if it.options.StopID != "" && CompareIDs(msg.ID, it.options.StopID) >= 0 {
+			return true
+		}
+		return it.options.Before != 0 && msg.CreatedAt > it.options.Before
+	}
+
+	if it.options.StopID != "" && CompareIDs(msg.ID, it.options.StopID) <= 0 {
+		return true
+	}
+	return it.options.After != 0 && msg.CreatedAt < it.options.After
+}
+
+// skipped reports whether msg is before the start of the iteration
+func (it *MessageIterator) skipped(msg *Message) bool {
+	if it.options.Direction == IterateForward {
+		return it.options.After != 0 && msg.CreatedAt < it.options.After
+	}
+	return it.options.Before != 0 && msg.CreatedAt > it.options.Before
+}
+
+// CompareIDs compares two numeric GroupMe IDs, returning -1, 0 or 1
+// if a is less than, equal to, or greater than b
+func CompareIDs(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
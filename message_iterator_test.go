@@ -0,0 +1,179 @@
+package groupme
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyServer serves count messages, with IDs 1 through count,
+// each created 10 seconds after the previous
+func historyServer(count int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		var messages []*Message
+		if afterID := query.Get("after_id"); afterID != "" {
+			after, _ := strconv.Atoi(afterID)
+			for id := after + 1; id <= count && len(messages) < limit; id++ {
+				messages = append(messages, &Message{ID: strconv.Itoa(id), CreatedAt: Timestamp(id * 10)})
+			}
+		} else {
+			before := count + 1
+			if beforeID := query.Get("before_id"); beforeID != "" {
+				before, _ = strconv.Atoi(beforeID)
+			}
+			for id := before - 1; id > 0 && len(messages) < limit; id-- {
+				messages = append(messages, &Message{ID: strconv.Itoa(id), CreatedAt: Timestamp(id * 10)})
+			}
+		}
+
+		if len(messages) == 0 {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": IndexMessagesResponse{Count: count, Messages: messages},
+		})
+	}))
+}
+
+func collectIDs(t *testing.T, iter *MessageIterator) []int {
+	var ids []int
+	for iter.Next(context.Background()) {
+		id, err := strconv.Atoi(iter.Message().ID)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	require.NoError(t, iter.Err())
+	return ids
+}
+
+func TestMessageIterator(t *testing.T) {
+	server := historyServer(250)
+	defer server.Close()
+
+	client := NewClient("")
+	client.apiEndpointBase = server.URL
+
+	t.Run("Backward", func(t *testing.T) {
+		ids := collectIDs(t, client.IterateMessages("1", nil))
+		require.Len(t, ids, 250)
+		assert.Equal(t, 250, ids[0])
+		assert.Equal(t, 1, ids[249])
+	})
+
+	t.Run("Forward", func(t *testing.T) {
+		ids := collectIDs(t, client.IterateMessages("1", &MessageIteratorOptions{
+			Direction: IterateForward,
+			PageSize:  30,
+		}))
+		require.Len(t, ids, 250)
+		assert.Equal(t, 1, ids[0])
+		assert.Equal(t, 250, ids[249])
+	})
+
+	t.Run("StartStopIDs", func(t *testing.T) {
+		ids := collectIDs(t, client.IterateMessages("1", &MessageIteratorOptions{
+			StartID: "200",
+			StopID:  "50",
+		}))
+		require.Len(t, ids, 149)
+		assert.Equal(t, 199, ids[0])
+		assert.Equal(t, 51, ids[148])
+
+		ids = collectIDs(t, client.IterateMessages("1", &MessageIteratorOptions{
+			Direction: IterateForward,
+			StartID:   "50",
+			StopID:    "200",
+		}))
+		require.Len(t, ids, 149)
+		assert.Equal(t, 51, ids[0])
+		assert.Equal(t, 199, ids[148])
+	})
+
+	t.Run("TimeBounds", func(t *testing.T) {
+		options := &MessageIteratorOptions{
+			After:  Timestamp(1000),
+			Before: Timestamp(1500),
+		}
+		ids := collectIDs(t, client.IterateMessages("1", options))
+		require.Len(t, ids, 51)
+		assert.Equal(t, 150, ids[0])
+		assert.Equal(t, 100, ids[50])
+
+		options.Direction = IterateForward
+		ids = collectIDs(t, client.IterateMessages("1", options))
+		require.Len(t, ids, 51)
+		assert.Equal(t, 100, ids[0])
+		assert.Equal(t, 150, ids[50])
+	})
+}
+
+func TestMessageIterator_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	client.apiEndpointBase = server.URL
+
+	iter := client.IterateMessages("1", nil)
+	assert.False(t, iter.Next(context.Background()))
+	assert.Error(t, iter.Err())
+	assert.Nil(t, iter.Message())
+}
+
+func TestCompareIDs(t *testing.T) {
+	assert.Equal(t, 0, CompareIDs("123", "123"))
+	assert.Equal(t, -1, CompareIDs("99", "100"))
+	assert.Equal(t, 1, CompareIDs("100", "99"))
+	assert.Equal(t, 0, CompareIDs("0", ""))
+}
+
+func TestDirectMessageIterator(t *testing.T) {
+	const count = 45
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		assert.Equal(t, "42", query.Get("other_user_id"))
+
+		before := count + 1
+		if beforeID := query.Get("before_id"); beforeID != "" {
+			before, _ = strconv.Atoi(beforeID)
+		}
+		var messages []*Message
+		for id := before - 1; id > 0 && len(messages) < 20; id-- {
+			messages = append(messages, &Message{ID: strconv.Itoa(id)})
+		}
+
+		if len(messages) == 0 {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": IndexDirectMessagesResponse{Count: count, Messages: messages},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	client.apiEndpointBase = server.URL
+
+	ids := collectIDs(t, client.IterateDirectMessages("42", &MessageIteratorOptions{StopID: "5"}))
+	require.Len(t, ids, 40)
+	assert.Equal(t, 45, ids[0])
+	assert.Equal(t, 6, ids[39])
+
+	iter := client.IterateDirectMessages("42", &MessageIteratorOptions{Direction: IterateForward})
+	assert.False(t, iter.Next(context.Background()))
+	assert.Error(t, iter.Err())
+}
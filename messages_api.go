@@ -125,6 +125,10 @@ The character map is an array of arrays containing rune data
 The placeholder should be a high-point/invisible UTF-8 character.
 */
 func (c *Client) CreateMessage(ctx context.Context, groupID string, m *Message) (*Message, error) {
+	if err := c.validate(validID("group_id", groupID), m.validate); err != nil {
+		return nil, err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase+createMessagesEndpoint, groupID)
 
 	m.SourceGUID = uuid.New().String()
@@ -147,6 +151,10 @@ func (c *Client) CreateMessage(ctx context.Context, groupID string, m *Message)
 	var resp struct {
 		*Message `json:"message"`
 	}
+
+	// The SourceGUID lets the server deduplicate, so the request is safe to retry
+	ctx = withIdempotency(ctx)
+	ctx = withRateLimitScope(ctx, RequestClassMessage, "group:"+groupID)
 	err = c.doWithAuthToken(ctx, httpReq, &resp)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,597 @@
+package groupme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// GroupMe documentation: https://dev.groupme.com/tutorials/push
+
+// GroupMePushBase is the GroupMe Faye (Bayeux protocol) push service.
+// Overridable with WithPushBaseURL
+const GroupMePushBase = "https://push.groupme.com/faye"
+
+// WithPushBaseURL connects push clients to baseURL instead of GroupMePushBase
+func WithPushBaseURL(baseURL string) ClientOption {
+	return func(client *client) {
+		client.pushEndpointBase = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+/*//////// Events ////////*/
+
+type pushEventType string
+
+// Push event types
+const (
+	PushLineCreate          pushEventType = "line.create"
+	PushDirectMessageCreate pushEventType = "direct_message.create"
+	PushLikeCreate          pushEventType = "like.create"
+	PushMembershipCreate    pushEventType = "membership.create"
+	PushTyping              pushEventType = "typing"
+	PushPing                pushEventType = "ping"
+)
+
+// PushEvent is an event delivered by the push service
+type PushEvent struct {
+	// Channel the event was delivered on, e.g. /user/123 or /group/456
+	Channel string
+	Type    pushEventType
+	// Notification text, if any
+	Alert string
+	// The created message for line.create and direct_message.create,
+	// the liked message for like.create
+	Message *Message
+	// The group joined for membership.create
+	Group *Group
+	// The user who liked for like.create, or is typing for typing
+	UserID string
+	// When the user started typing
+	Started Timestamp
+	// The raw event data, for event types without typed fields
+	Raw json.RawMessage
+}
+
+func (e PushEvent) String() string {
+	return marshal(&e)
+}
+
+func parsePushEvent(channel string, data json.RawMessage) (*PushEvent, error) {
+	var raw struct {
+		Type    pushEventType   `json:"type"`
+		Alert   string          `json:"alert"`
+		Subject json.RawMessage `json:"subject"`
+		UserID  string          `json:"user_id"`
+		Started Timestamp       `json:"started"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	event := &PushEvent{
+		Channel: channel,
+		Type:    raw.Type,
+		Alert:   raw.Alert,
+		UserID:  raw.UserID,
+		Started: raw.Started,
+		Raw:     data,
+	}
+
+	switch raw.Type {
+	case PushLineCreate, PushDirectMessageCreate:
+		event.Message = &Message{}
+		if err := json.Unmarshal(raw.Subject, event.Message); err != nil {
+			return nil, err
+		}
+	case PushLikeCreate:
+		var subject struct {
+			Line   *Message `json:"line"`
+			UserID string   `json:"user_id"`
+		}
+		if err := json.Unmarshal(raw.Subject, &subject); err != nil {
+			return nil, err
+		}
+		event.Message = subject.Line
+		event.UserID = subject.UserID
+	case PushMembershipCreate:
+		event.Group = &Group{}
+		if err := json.Unmarshal(raw.Subject, event.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	return event, nil
+}
+
+/*//////// Bayeux ////////*/
+
+const (
+	bayeuxHandshakeChannel   = "/meta/handshake"
+	bayeuxConnectChannel     = "/meta/connect"
+	bayeuxSubscribeChannel   = "/meta/subscribe"
+	bayeuxVersion            = "1.0"
+	bayeuxAdviceHandshake    = "handshake"
+	bayeuxAdviceNone         = "none"
+	bayeuxConnectionLongPoll = "long-polling"
+	bayeuxConnectionSocket   = "websocket"
+)
+
+type bayeuxMessage struct {
+	Channel                  string          `json:"channel"`
+	ID                       string          `json:"id,omitempty"`
+	ClientID                 string          `json:"clientId,omitempty"`
+	Version                  string          `json:"version,omitempty"`
+	SupportedConnectionTypes []string        `json:"supportedConnectionTypes,omitempty"`
+	ConnectionType           string          `json:"connectionType,omitempty"`
+	Subscription             string          `json:"subscription,omitempty"`
+	Successful               *bool           `json:"successful,omitempty"`
+	Error                    string          `json:"error,omitempty"`
+	Advice                   *bayeuxAdvice   `json:"advice,omitempty"`
+	Ext                      *bayeuxExt      `json:"ext,omitempty"`
+	Data                     json.RawMessage `json:"data,omitempty"`
+}
+
+type bayeuxAdvice struct {
+	Reconnect string `json:"reconnect,omitempty"`
+	Interval  int    `json:"interval,omitempty"`
+	Timeout   int    `json:"timeout,omitempty"`
+}
+
+type bayeuxExt struct {
+	AccessToken string    `json:"access_token,omitempty"`
+	Timestamp   Timestamp `json:"timestamp,omitempty"`
+}
+
+func (m bayeuxMessage) successful() bool {
+	return m.Successful != nil && *m.Successful
+}
+
+func (m bayeuxMessage) meta() bool {
+	return strings.HasPrefix(m.Channel, "/meta/")
+}
+
+/*//////// Transports ////////*/
+
+type pushTransport string
+
+// Push transports
+const (
+	PushTransportLongPolling pushTransport = bayeuxConnectionLongPoll
+	PushTransportWebSocket   pushTransport = bayeuxConnectionSocket
+)
+
+// transport exchanges Bayeux messages with the push service. It returns
+// every message received until each sent message has been replied to
+type transport interface {
+	exchange(ctx context.Context, msgs []bayeuxMessage) ([]bayeuxMessage, error)
+	close() error
+}
+
+type longPollingTransport struct {
+	httpClient *http.Client
+	url        string
+}
+
+func (t *longPollingTransport) exchange(ctx context.Context, msgs []bayeuxMessage) ([]bayeuxMessage, error) {
+	jsonBytes, err := json.Marshal(msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= errorStatusCodeMin {
+		return nil, fmt.Errorf("push service responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var replies []bayeuxMessage
+	if err := json.NewDecoder(resp.Body).Decode(&replies); err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+func (t *longPollingTransport) close() error {
+	return nil
+}
+
+type webSocketTransport struct {
+	httpClient *http.Client
+	url        string
+	conn       *websocket.Conn
+}
+
+func (t *webSocketTransport) exchange(ctx context.Context, msgs []bayeuxMessage) ([]bayeuxMessage, error) {
+	if t.conn == nil {
+		dialer := websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: 30 * time.Second,
+			Jar:              t.httpClient.Jar,
+		}
+
+		wsURL := "ws" + strings.TrimPrefix(t.url, "http")
+		conn, _, err := dialer.DialContext(ctx, wsURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		t.conn = conn
+	}
+
+	// Unblock reads when the context is done
+	conn := t.conn
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	if err := conn.WriteJSON(msgs); err != nil {
+		_ = t.close()
+		return nil, err
+	}
+
+	pending := map[string]bool{}
+	for _, msg := range msgs {
+		pending[msg.ID] = true
+	}
+
+	var received []bayeuxMessage
+	for len(pending) > 0 {
+		var batch []bayeuxMessage
+		if err := conn.ReadJSON(&batch); err != nil {
+			_ = t.close()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		for _, msg := range batch {
+			if msg.meta() {
+				delete(pending, msg.ID)
+			}
+		}
+		received = append(received, batch...)
+	}
+
+	return received, nil
+}
+
+func (t *webSocketTransport) close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+/*//////// Push Client ////////*/
+
+// ErrPushClientRan is returned by Run when it was already called
+var ErrPushClientRan = errors.New("groupme: PushClient.Run was already called")
+
+// PushClient receives realtime events from the GroupMe push service
+// over the Bayeux protocol, as an alternative to polling or bot callbacks.
+//
+//	push := groupme.NewPushClient(token)
+//	push.SubscribeUser(userID)
+//	go push.Run(ctx)
+//	for event := range push.Events() {
+//		...
+//	}
+type PushClient struct {
+	client
+	authorizationToken string
+	transportType      pushTransport
+	errorHandler       func(err error)
+	backoff            RetryPolicy
+	events             chan *PushEvent
+
+	mu            sync.Mutex
+	ran           bool
+	subscriptions []string
+	clientID      string
+	nextID        int
+	transport     transport
+}
+
+// PushClientOption configures a PushClient
+type PushClientOption func(*PushClient)
+
+// WithPushTransport sets the transport used to connect. Defaults to long-polling
+func WithPushTransport(transport pushTransport) PushClientOption {
+	return func(p *PushClient) {
+		p.transportType = transport
+	}
+}
+
+// WithPushErrorHandler sets the function called with connection failures,
+// which are retried with backoff, and with events that can't be parsed,
+// which are skipped
+func WithPushErrorHandler(handler func(err error)) PushClientOption {
+	return func(p *PushClient) {
+		p.errorHandler = handler
+	}
+}
+
+// WithPushClientOptions applies ClientOptions, such as WithHTTPClient
+// or WithPushBaseURL, to the PushClient
+func WithPushClientOptions(options ...ClientOption) PushClientOption {
+	return func(p *PushClient) {
+		for _, option := range options {
+			option(&p.client)
+		}
+	}
+}
+
+// NewPushClient creates a new PushClient authenticated with the token
+func NewPushClient(authToken string, options ...PushClientOption) *PushClient {
+	p := &PushClient{
+		client:             newClient(nil),
+		authorizationToken: authToken,
+		transportType:      PushTransportLongPolling,
+		errorHandler:       func(error) {},
+		backoff:            RetryPolicy{MinBackoff: time.Second, MaxBackoff: time.Minute, Jitter: 0.2},
+		events:             make(chan *PushEvent, 64),
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// SubscribeUser subscribes to the user's channel, which receives events for every
+// group and direct message conversation they're part of. Must be the token's user
+func (p *PushClient) SubscribeUser(userID string) {
+	p.subscribe("/user/" + userID)
+}
+
+// SubscribeGroup subscribes to the group's channel, which receives typing events
+func (p *PushClient) SubscribeGroup(groupID string) {
+	p.subscribe("/group/" + groupID)
+}
+
+// SubscribeDirectMessage subscribes to the direct message conversation's channel
+func (p *PushClient) SubscribeDirectMessage(conversationID string) {
+	p.subscribe("/direct_message/" + strings.ReplaceAll(conversationID, "+", "_"))
+}
+
+func (p *PushClient) subscribe(channel string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscriptions = append(p.subscriptions, channel)
+	// Resubscribe everything on the next connection
+	p.clientID = ""
+}
+
+// Events returns the channel events are delivered on. It is closed when Run returns
+func (p *PushClient) Events() <-chan *PushEvent {
+	return p.events
+}
+
+// Close safely shuts down the PushClient's connections
+func (p *PushClient) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.transport != nil {
+		_ = p.transport.close()
+		p.transport = nil
+	}
+	return p.client.Close()
+}
+
+// Run connects to the push service and delivers events until the context
+// is done, reconnecting with backoff whenever the connection fails.
+// Returns the context's error.
+//
+// Run may only be called once, as it closes the Events channel and the
+// PushClient when it returns. Later calls return ErrPushClientRan
+func (p *PushClient) Run(ctx context.Context) error {
+	p.mu.Lock()
+	ran := p.ran
+	p.ran = true
+	p.mu.Unlock()
+	if ran {
+		return ErrPushClientRan
+	}
+
+	defer close(p.events)
+	defer p.Close()
+
+	for failures := 0; ; {
+		err := p.connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err == nil {
+			failures = 0
+			continue
+		}
+		p.errorHandler(err)
+
+		// Start over with a new handshake
+		p.mu.Lock()
+		p.clientID = ""
+		if p.transport != nil {
+			_ = p.transport.close()
+		}
+		p.mu.Unlock()
+
+		failures++
+		timer := time.NewTimer(p.backoff.backoff(failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// connect performs a handshake and subscribes if needed,
+// then a single connect, delivering any received events
+func (p *PushClient) connect(ctx context.Context) error {
+	p.mu.Lock()
+	if p.transport == nil {
+		if p.transportType == PushTransportWebSocket {
+			p.transport = &webSocketTransport{httpClient: p.httpClient, url: p.pushEndpointBase}
+		} else {
+			p.transport = &longPollingTransport{httpClient: p.httpClient, url: p.pushEndpointBase}
+		}
+	}
+	t := p.transport
+	needsHandshake := p.clientID == ""
+	p.mu.Unlock()
+
+	if needsHandshake {
+		if err := p.handshake(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	msg := bayeuxMessage{
+		Channel:        bayeuxConnectChannel,
+		ID:             p.newMessageID(),
+		ClientID:       p.clientID,
+		ConnectionType: string(p.transportType),
+	}
+	p.mu.Unlock()
+
+	replies, err := t.exchange(ctx, []bayeuxMessage{msg})
+	if err != nil {
+		return err
+	}
+
+	var connectErr error
+	for _, reply := range replies {
+		if reply.Channel == bayeuxConnectChannel {
+			if !reply.successful() {
+				connectErr = fmt.Errorf("push connect failed: %s", reply.Error)
+			}
+			if reply.Advice != nil && (reply.Advice.Reconnect == bayeuxAdviceHandshake || reply.Advice.Reconnect == bayeuxAdviceNone) {
+				p.mu.Lock()
+				p.clientID = ""
+				p.mu.Unlock()
+			}
+			continue
+		}
+		if reply.meta() || len(reply.Data) == 0 {
+			continue
+		}
+
+		event, err := parsePushEvent(reply.Channel, reply.Data)
+		if err != nil {
+			p.errorHandler(fmt.Errorf("push event on %s: %w", reply.Channel, err))
+			continue
+		}
+		if event.Type == PushPing {
+			continue
+		}
+
+		select {
+		case p.events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return connectErr
+}
+
+func (p *PushClient) handshake(ctx context.Context, t transport) error {
+	p.mu.Lock()
+	handshake := bayeuxMessage{
+		Channel:                  bayeuxHandshakeChannel,
+		ID:                       p.newMessageID(),
+		Version:                  bayeuxVersion,
+		SupportedConnectionTypes: []string{string(p.transportType)},
+	}
+	p.mu.Unlock()
+
+	replies, err := t.exchange(ctx, []bayeuxMessage{handshake})
+	if err != nil {
+		return err
+	}
+
+	var clientID string
+	for _, reply := range replies {
+		if reply.Channel == bayeuxHandshakeChannel {
+			if !reply.successful() {
+				return fmt.Errorf("push handshake failed: %s", reply.Error)
+			}
+			clientID = reply.ClientID
+		}
+	}
+	if clientID == "" {
+		return errors.New("push handshake returned no client ID")
+	}
+
+	p.mu.Lock()
+	var subscribes []bayeuxMessage
+	for _, channel := range p.subscriptions {
+		subscribes = append(subscribes, bayeuxMessage{
+			Channel:      bayeuxSubscribeChannel,
+			ID:           p.newMessageID(),
+			ClientID:     clientID,
+			Subscription: channel,
+			Ext: &bayeuxExt{
+				AccessToken: p.authorizationToken,
+				Timestamp:   FromTime(time.Now()),
+			},
+		})
+	}
+	p.mu.Unlock()
+
+	if len(subscribes) > 0 {
+		replies, err = t.exchange(ctx, subscribes)
+		if err != nil {
+			return err
+		}
+		for _, reply := range replies {
+			if reply.Channel == bayeuxSubscribeChannel && !reply.successful() {
+				return fmt.Errorf("push subscribe to %s failed: %s", reply.Subscription, reply.Error)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.clientID = clientID
+	p.mu.Unlock()
+
+	return nil
+}
+
+// newMessageID returns the next Bayeux message ID. Callers must hold the lock
+func (p *PushClient) newMessageID() string {
+	p.nextID++
+	return strconv.Itoa(p.nextID)
+}
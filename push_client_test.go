@@ -0,0 +1,138 @@
+package groupme
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePushEvent(t *testing.T) {
+	event, err := parsePushEvent("/user/1", json.RawMessage(`{
+		"type": "line.create",
+		"alert": "Alice: Hello",
+		"subject": {"id": "10", "group_id": "2", "text": "Hello"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, PushLineCreate, event.Type)
+	assert.Equal(t, "/user/1", event.Channel)
+	assert.Equal(t, "Alice: Hello", event.Alert)
+	require.NotNil(t, event.Message)
+	assert.Equal(t, "10", event.Message.ID)
+
+	event, err = parsePushEvent("/user/1", json.RawMessage(`{
+		"type": "like.create",
+		"subject": {"line": {"id": "10"}, "user_id": "3"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, "10", event.Message.ID)
+	assert.Equal(t, "3", event.UserID)
+
+	event, err = parsePushEvent("/user/1", json.RawMessage(`{
+		"type": "membership.create",
+		"subject": {"id": "2", "name": "Group"}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, event.Group)
+	assert.Equal(t, "Group", event.Group.Name)
+
+	event, err = parsePushEvent("/group/2", json.RawMessage(`{"type": "typing", "user_id": "3", "started": 1600000000}`))
+	require.NoError(t, err)
+	assert.Equal(t, PushTyping, event.Type)
+	assert.Equal(t, "3", event.UserID)
+	assert.Equal(t, Timestamp(1600000000), event.Started)
+	assert.Nil(t, event.Message)
+}
+
+// bayeuxStandIn is a long-polling push service that is unavailable for
+// its first requests, then delivers a malformed and a valid event
+type bayeuxStandIn struct {
+	unavailable int32
+	connects    int32
+}
+
+func (b *bayeuxStandIn) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if atomic.AddInt32(&b.unavailable, -1) >= 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var msgs []bayeuxMessage
+	if err := json.NewDecoder(req.Body).Decode(&msgs); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	successful := true
+	var replies []bayeuxMessage
+	for _, msg := range msgs {
+		reply := bayeuxMessage{Channel: msg.Channel, ID: msg.ID, ClientID: "client", Subscription: msg.Subscription, Successful: &successful}
+		replies = append(replies, reply)
+
+		if msg.Channel == bayeuxConnectChannel {
+			if atomic.AddInt32(&b.connects, 1) > 1 {
+				// Nothing new, wait as a long poll would
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			replies = append(replies,
+				bayeuxMessage{Channel: "/user/1", Data: json.RawMessage(`{"type": "line.create", "subject": "malformed"}`)},
+				bayeuxMessage{Channel: "/user/1", Data: json.RawMessage(`{"type": "line.create", "subject": {"id": "10", "text": "Hello"}}`)},
+			)
+		}
+	}
+	_ = json.NewEncoder(w).Encode(replies)
+}
+
+func TestPushClient_Run(t *testing.T) {
+	server := httptest.NewServer(&bayeuxStandIn{unavailable: 2})
+	defer server.Close()
+
+	var mu sync.Mutex
+	var errs []error
+	push := NewPushClient("token",
+		WithPushClientOptions(WithPushBaseURL(server.URL)),
+		WithPushErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	push.backoff = RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	push.SubscribeUser("1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- push.Run(ctx) }()
+
+	// Reconnects after the failures, skipping the malformed event
+	select {
+	case event := <-push.Events():
+		require.NotNil(t, event.Message)
+		assert.Equal(t, "Hello", event.Message.Text)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no push event")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	_, open := <-push.Events()
+	assert.False(t, open)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "503")
+	assert.Contains(t, errs[1].Error(), "503")
+	assert.Contains(t, errs[2].Error(), "push event on /user/1")
+
+	// Run closed the PushClient, so it can't run again
+	assert.ErrorIs(t, push.Run(context.Background()), ErrPushClientRan)
+}
@@ -0,0 +1,260 @@
+package groupme
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limit is the rate and burst size of a token bucket
+type Limit struct {
+	// Tokens added to the bucket per second. Zero means unlimited
+	Rate float64
+	// Maximum number of tokens the bucket holds. Values less than 1 are treated as 1
+	Burst int
+}
+
+// PerMinute returns a Limit allowing n requests per minute, with a burst of burst
+func PerMinute(n int, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// RequestClass is a kind of request that can be given its own Limit
+type RequestClass string
+
+const (
+	// Requests that don't modify anything (GET)
+	RequestClassRead RequestClass = "read"
+	// Requests that modify something, other than posting messages
+	RequestClassWrite RequestClass = "write"
+	// Requests that post a message to a group, chat or as a bot
+	RequestClassMessage RequestClass = "message"
+)
+
+// RateLimiter is a client side token bucket rate limiter. A single
+// RateLimiter can be shared by any number of Clients and BotClients
+// so that they throttle together.
+//
+// Every request takes a token from the global bucket, then from the
+// bucket of its request class, and, for posted messages, from the bucket
+// of the group, chat or bot it is posted to. Requests block until a
+// token is available or their context is done.
+type RateLimiter struct {
+	global       Limit
+	classes      map[RequestClass]Limit
+	conversation Limit
+	mu           sync.Mutex
+	buckets      map[string]*tokenBucket
+	swept        time.Time
+}
+
+// How often buckets are checked for eviction
+const bucketSweepInterval = time.Minute
+
+// RateLimiterOption configures optional RateLimiter buckets
+type RateLimiterOption func(*RateLimiter)
+
+// WithClassLimit adds a bucket shared by every request of the class
+func WithClassLimit(class RequestClass, limit Limit) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.classes[class] = limit
+	}
+}
+
+// WithConversationLimit adds a bucket for each group, chat or bot
+// messages are posted to
+func WithConversationLimit(limit Limit) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.conversation = limit
+	}
+}
+
+// NewRateLimiter creates a RateLimiter with a global limit across all requests
+func NewRateLimiter(global Limit, options ...RateLimiterOption) *RateLimiter {
+	limiter := &RateLimiter{
+		global:  global,
+		classes: map[RequestClass]Limit{},
+		buckets: map[string]*tokenBucket{},
+	}
+
+	for _, option := range options {
+		option(limiter)
+	}
+
+	return limiter
+}
+
+// WithRateLimiter throttles every request the client makes through the limiter
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(client *client) {
+		client.rateLimiter = limiter
+	}
+}
+
+/*/// Request scope ///*/
+
+type rateLimitScopeKey struct{}
+
+type rateLimitScope struct {
+	class        RequestClass
+	conversation string
+}
+
+// withRateLimitScope marks requests made with the context as belonging to the
+// request class and conversation. Requests without a scope are classified
+// by their HTTP method
+func withRateLimitScope(ctx context.Context, class RequestClass, conversation string) context.Context {
+	return context.WithValue(ctx, rateLimitScopeKey{}, rateLimitScope{class, conversation})
+}
+
+func requestScope(req *http.Request) rateLimitScope {
+	if scope, ok := req.Context().Value(rateLimitScopeKey{}).(rateLimitScope); ok {
+		return scope
+	}
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return rateLimitScope{class: RequestClassRead}
+	}
+	return rateLimitScope{class: RequestClassWrite}
+}
+
+/*/// Waiting ///*/
+
+// Wait blocks until the request may be sent, or the context is done.
+// If the context is done, the tokens already taken are returned
+func (l *RateLimiter) Wait(ctx context.Context, req *http.Request) error {
+	scope := requestScope(req)
+
+	var taken []*tokenBucket
+	take := func(key string, limit Limit) error {
+		bucket, err := l.wait(ctx, key, limit)
+		if err != nil {
+			l.mu.Lock()
+			for _, bucket := range taken {
+				bucket.cancel()
+			}
+			l.mu.Unlock()
+			return err
+		}
+		if bucket != nil {
+			taken = append(taken, bucket)
+		}
+		return nil
+	}
+
+	if err := take("", l.global); err != nil {
+		return err
+	}
+
+	if limit, ok := l.classes[scope.class]; ok {
+		if err := take("class:"+string(scope.class), limit); err != nil {
+			return err
+		}
+	}
+
+	if scope.conversation != "" && l.conversation.Rate > 0 {
+		if err := take("conversation:"+scope.conversation, l.conversation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wait takes a token from the bucket of the key, returning the bucket,
+// or nil if the limit is unlimited
+func (l *RateLimiter) wait(ctx context.Context, key string, limit Limit) (*tokenBucket, error) {
+	if limit.Rate <= 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	l.sweep(now)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(limit)
+		l.buckets[key] = bucket
+	}
+	delay := bucket.reserve(now)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return bucket, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		bucket.cancel()
+		l.mu.Unlock()
+		return nil, ctx.Err()
+	case <-timer.C:
+		return bucket, nil
+	}
+}
+
+// sweep evicts the buckets that have refilled, so there is no bucket for
+// each group, chat or bot messages were ever posted to. A full bucket
+// is the same as a new one. Must be called with l.mu held
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < bucketSweepInterval {
+		return
+	}
+	l.swept = now
+
+	for key, bucket := range l.buckets {
+		if bucket.full(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+/*/// Token Bucket ///*/
+
+type tokenBucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit Limit) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+	}
+}
+
+// reserve takes a token, returning how long to wait until it's available.
+// Tokens may go negative, queueing reservations behind each other
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+		if b.tokens > float64(b.limit.Burst) {
+			b.tokens = float64(b.limit.Burst)
+		}
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
+
+// full reports whether the bucket has refilled to its burst size by now
+func (b *tokenBucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= float64(b.limit.Burst)
+}
+
+// cancel returns a reserved token that won't be used
+func (b *tokenBucket) cancel() {
+	b.tokens++
+}
@@ -0,0 +1,122 @@
+package groupme
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_reserve(t *testing.T) {
+	bucket := newTokenBucket(Limit{Rate: 10, Burst: 2})
+	now := time.Now()
+
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve(now))
+
+	// Refilled, but never above the burst size
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(time.Minute)))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(time.Minute)))
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve(now.Add(time.Minute)))
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(
+		Limit{},
+		WithClassLimit(RequestClassMessage, Limit{Rate: 1000, Burst: 10}),
+		WithConversationLimit(Limit{Rate: 1, Burst: 1}),
+	)
+
+	postTo := func(ctx context.Context, groupID string) error {
+		req, err := http.NewRequest("POST", "", nil)
+		require.NoError(t, err)
+		ctx = withRateLimitScope(ctx, RequestClassMessage, "group:"+groupID)
+		return limiter.Wait(ctx, req.WithContext(ctx))
+	}
+
+	// Unlimited reads
+	for i := 0; i < 100; i++ {
+		req, err := http.NewRequest("GET", "", nil)
+		require.NoError(t, err)
+		require.NoError(t, limiter.Wait(context.Background(), req))
+	}
+
+	// Groups have separate buckets
+	require.NoError(t, postTo(context.Background(), "1"))
+	require.NoError(t, postTo(context.Background(), "2"))
+
+	// An exhausted bucket blocks until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, postTo(ctx, "1"), context.DeadlineExceeded)
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	limiter := NewRateLimiter(
+		Limit{Rate: 1, Burst: 2},
+		WithClassLimit(RequestClassMessage, Limit{Rate: 1, Burst: 2}),
+		WithConversationLimit(Limit{Rate: 1, Burst: 1}),
+	)
+	postTo := func(groupID string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		ctx = withRateLimitScope(ctx, RequestClassMessage, "group:"+groupID)
+		return limiter.Wait(ctx, httptest.NewRequest("POST", "/", nil).WithContext(ctx))
+	}
+
+	require.NoError(t, postTo("1"))
+	assert.ErrorIs(t, postTo("1"), context.DeadlineExceeded)
+
+	// The global and class tokens taken by the cancelled request were returned
+	assert.NoError(t, postTo("2"))
+}
+
+func TestRateLimiter_sweep(t *testing.T) {
+	limiter := NewRateLimiter(Limit{}, WithConversationLimit(Limit{Rate: 1, Burst: 1}))
+	for _, groupID := range []string{"1", "2", "3"} {
+		ctx := withRateLimitScope(context.Background(), RequestClassMessage, "group:"+groupID)
+		require.NoError(t, limiter.Wait(ctx, httptest.NewRequest("POST", "/", nil).WithContext(ctx)))
+	}
+	require.Len(t, limiter.buckets, 3)
+
+	// Idle buckets are evicted once they have refilled
+	later := time.Now().Add(bucketSweepInterval)
+	limiter.buckets["conversation:group:1"].reserve(later)
+	limiter.sweep(later)
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "conversation:group:1")
+}
+
+func TestRateLimiter_SharedClients(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"response": {}}`)
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(Limit{Rate: 1, Burst: 1})
+
+	client := NewClient("", WithRateLimiter(limiter))
+	client.apiEndpointBase = server.URL
+	botClient := NewBotClient("", WithRateLimiter(limiter))
+	botClient.apiEndpointBase = server.URL
+
+	_, err := client.ShowGroup(context.Background(), "1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = botClient.PostBotMessage(ctx, "Test", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,196 @@
+package groupme
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StatusEnhanceYourCalm is the non-standard status code GroupMe returns when
+// a client is being rate limited
+const StatusEnhanceYourCalm = 420
+
+// RetryPolicy defines when and how often a failed request is retried.
+// Requests are retried on transport errors and on 420, 429 and 5XX
+// responses, waiting an exponentially increasing, jittered backoff
+// between attempts. A Retry-After response header takes precedence
+// over the computed backoff.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first. Values less
+	// than 1 are treated as 1
+	MaxAttempts int
+	// Backoff before the first retry, doubled for each following retry
+	MinBackoff time.Duration
+	// Upper bound of a single backoff
+	MaxBackoff time.Duration
+	// Upper bound of the time spent on all attempts. Zero means
+	// no bound other than the request context
+	MaxElapsed time.Duration
+	// Fraction (0-1) of each backoff that is randomized
+	Jitter float64
+	// If true, POST requests that are not known to be idempotent
+	// are retried as well. Messages carrying a SourceGUID are
+	// always safe to retry
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for most clients
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		MaxElapsed:  2 * time.Minute,
+		Jitter:      0.2,
+	}
+}
+
+// WithRetryPolicy sets the retry policy used by every request the client makes
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(client *client) {
+		client.retryPolicy = &policy
+	}
+}
+
+// retryableStatus reports whether a response status code is worth retrying
+func retryableStatus(code int) bool {
+	switch code {
+	case StatusEnhanceYourCalm,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+/*/// Idempotency ///*/
+
+type idempotentKey struct{}
+
+// withIdempotency marks requests made with the context as safe to retry,
+// regardless of their HTTP method
+func withIdempotency(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func (p *RetryPolicy) canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body can't be rewound
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	if idempotent, _ := req.Context().Value(idempotentKey{}).(bool); idempotent {
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+/*/// Backoff ///*/
+
+// backoff returns the wait before the given retry, starting at 1
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	wait := float64(p.MinBackoff) * math.Pow(2, float64(retry-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		// nolint // weak random generator is ok for jitter
+		wait -= wait * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(wait)
+}
+
+// retryAfter parses the Retry-After header, which is either a number
+// of seconds or an HTTP date. Returns 0 if absent or invalid
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+/*/// Execution ///*/
+
+// attempt performs a single request, returning the response (if any was
+// received) along with the resulting error
+type attempt func(req *http.Request) (*http.Response, error)
+
+func (p *RetryPolicy) run(ctx context.Context, req *http.Request, do attempt) error {
+	if !p.canRetry(req) {
+		_, err := do(req)
+		return err
+	}
+
+	var deadline time.Time
+	if p.MaxElapsed > 0 {
+		deadline = time.Now().Add(p.MaxElapsed)
+	}
+	if ctxDeadline, ok := ctx.Deadline(); ok && (deadline.IsZero() || ctxDeadline.Before(deadline)) {
+		deadline = ctxDeadline
+	}
+
+	for try := 1; ; try++ {
+		if try > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			req.Body = body
+		}
+
+		resp, err := do(req)
+		if err == nil || try >= p.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		var wait time.Duration
+		if resp == nil {
+			// Transport error
+			wait = p.backoff(try)
+		} else if retryableStatus(resp.StatusCode) {
+			wait = retryAfter(resp.Header)
+			if wait == 0 {
+				wait = p.backoff(try)
+			}
+		} else {
+			return err
+		}
+
+		if !deadline.IsZero() && time.Now().Add(wait).After(deadline) {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
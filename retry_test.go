@@ -0,0 +1,146 @@
+package groupme
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+	}
+}
+
+// failingServer responds with the given status until it has been called fails times
+func failingServer(fails int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= fails {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"response": {}}`)
+	}))
+	return server, &calls
+}
+
+func TestRetryPolicy_RetriesRateLimited(t *testing.T) {
+	server, calls := failingServer(2, StatusEnhanceYourCalm, nil)
+	defer server.Close()
+
+	client := NewClient("", WithRetryPolicy(testRetryPolicy()))
+	client.apiEndpointBase = server.URL
+
+	_, err := client.ShowGroup(context.Background(), "1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(calls))
+}
+
+func TestRetryPolicy_MaxAttempts(t *testing.T) {
+	server, calls := failingServer(5, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	client := NewClient("", WithRetryPolicy(testRetryPolicy()))
+	client.apiEndpointBase = server.URL
+
+	_, err := client.ShowGroup(context.Background(), "1")
+	require.Error(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(calls))
+}
+
+func TestRetryPolicy_NotRetryable(t *testing.T) {
+	server, calls := failingServer(5, http.StatusBadRequest, nil)
+	defer server.Close()
+
+	client := NewClient("", WithRetryPolicy(testRetryPolicy()))
+	client.apiEndpointBase = server.URL
+
+	_, err := client.ShowGroup(context.Background(), "1")
+	require.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+}
+
+func TestRetryPolicy_NonIdempotent(t *testing.T) {
+	server, calls := failingServer(1, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	client := NewClient("", WithRetryPolicy(testRetryPolicy()))
+	client.apiEndpointBase = server.URL
+
+	// Not retried by default
+	err := client.DestroyGroup(context.Background(), "1")
+	require.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	// Messages carry a SourceGUID, so they are retried
+	atomic.StoreInt32(calls, 0)
+	_, err = client.CreateMessage(context.Background(), "1", &Message{Text: "Test"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+
+	// Opted in
+	atomic.StoreInt32(calls, 0)
+	policy := testRetryPolicy()
+	policy.RetryNonIdempotent = true
+	client = NewClient("", WithRetryPolicy(policy))
+	client.apiEndpointBase = server.URL
+
+	err = client.DestroyGroup(context.Background(), "1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+}
+
+func TestRetryPolicy_RetryAfterExceedsDeadline(t *testing.T) {
+	server, calls := failingServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}})
+	defer server.Close()
+
+	client := NewClient("", WithRetryPolicy(testRetryPolicy()))
+	client.apiEndpointBase = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.ShowGroup(ctx, "1")
+	require.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), retryAfter(http.Header{}))
+	assert.Equal(t, 5*time.Second, retryAfter(http.Header{"Retry-After": {"5"}}))
+	assert.Equal(t, time.Duration(0), retryAfter(http.Header{"Retry-After": {"soon"}}))
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	assert.Greater(t, int64(retryAfter(http.Header{"Retry-After": {date}})), int64(time.Minute))
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+
+	policy.Jitter = 0.5
+	for retry := 1; retry < 5; retry++ {
+		wait := policy.backoff(retry)
+		assert.LessOrEqual(t, int64(wait), int64(5*time.Second))
+		assert.GreaterOrEqual(t, int64(wait), int64(500*time.Millisecond))
+	}
+}
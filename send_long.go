@@ -0,0 +1,281 @@
+package groupme
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// MaxTextLength is the most characters the text of a message may have
+const MaxTextLength = 1000
+
+type splitter struct {
+	length    int
+	numbering bool
+}
+
+// SplitOption configures how SplitMessage splits text
+type SplitOption func(*splitter)
+
+// WithSplitNumbering ends each part with its number, such as " (1/3)"
+func WithSplitNumbering() SplitOption {
+	return func(s *splitter) {
+		s.numbering = true
+	}
+}
+
+// WithSplitLength sets the most characters in each part. Defaults to MaxTextLength
+func WithSplitLength(length int) SplitOption {
+	return func(s *splitter) {
+		s.length = length
+	}
+}
+
+/*
+SplitMessage -
+
+Splits the message into messages whose text fits MaxTextLength, preferring
+to split between paragraphs, then sentences, then words. Mentions are never
+split, and each part mentions the users whose mentions fall in its text.
+The picture and other attachments are kept on the first part.
+
+A message that fits is returned as the only part
+*/
+func SplitMessage(m *Message, options ...SplitOption) []*Message {
+	s := splitter{length: MaxTextLength}
+	for _, option := range options {
+		option(&s)
+	}
+
+	text := []rune(m.Text)
+	if len(text) <= s.length {
+		return []*Message{m}
+	}
+
+	spans := mentionRuneSpans(m, text)
+
+	// Numbering takes space from each part, and the number of parts
+	// depends on that space, so grow the space until it suffices
+	var chunks [][2]int
+	for digits := 1; ; digits++ {
+		length := s.length
+		if s.numbering {
+			length -= len(numberingSuffix(1, 1)) - 2 + 2*digits
+		}
+		chunks = splitRunes(text, spans, length)
+		if !s.numbering || len(fmt.Sprint(len(chunks))) <= digits {
+			break
+		}
+	}
+
+	parts := make([]*Message, len(chunks))
+	for i, chunk := range chunks {
+		part := *m
+		part.Text = string(text[chunk[0]:chunk[1]])
+		part.Attachments = nil
+		if i > 0 {
+			part.ImageURL = ""
+		}
+
+		var mentions *Attachment
+		for _, span := range spans {
+			if span.start < chunk[0] || span.end > chunk[1] {
+				continue
+			}
+			if mentions == nil {
+				mentions = &Attachment{Type: Mentions}
+			}
+			mentions.UserIDs = append(mentions.UserIDs, span.userID)
+			mentions.Loci = append(mentions.Loci, []int{
+				utf16Len(string(text[chunk[0]:span.start])),
+				utf16Len(string(text[span.start:span.end])),
+			})
+		}
+
+		for _, attachment := range m.Attachments {
+			if attachment != nil && attachment.Type != Mentions && i == 0 {
+				part.Attachments = append(part.Attachments, attachment)
+			}
+		}
+		if mentions != nil {
+			part.Attachments = append(part.Attachments, mentions)
+		}
+
+		if s.numbering {
+			part.Text += numberingSuffix(i+1, len(chunks))
+		}
+		parts[i] = &part
+	}
+	return parts
+}
+
+func numberingSuffix(i, n int) string {
+	return fmt.Sprintf(" (%d/%d)", i, n)
+}
+
+// runeSpan is a mention, as rune indexes of the text
+type runeSpan struct {
+	userID     string
+	start, end int
+}
+
+// mentionRuneSpans converts the UTF-16 loci of the mentions to rune indexes
+func mentionRuneSpans(m *Message, text []rune) []runeSpan {
+	// runeIndex maps UTF-16 offsets at rune boundaries to rune indexes
+	runeIndex := make(map[int]int, len(text)+1)
+	offset := 0
+	for i, r := range text {
+		runeIndex[offset] = i
+		offset += len(utf16.Encode([]rune{r}))
+	}
+	runeIndex[offset] = len(text)
+
+	var spans []runeSpan
+	for _, span := range m.MentionSpans() {
+		start, okStart := runeIndex[span.Offset]
+		end, okEnd := runeIndex[span.Offset+span.Length]
+		if okStart && okEnd {
+			spans = append(spans, runeSpan{userID: span.UserID, start: start, end: end})
+		}
+	}
+	return spans
+}
+
+// splitRunes returns the [start, end) of each part of the text, with
+// the whitespace between parts trimmed. Text of only whitespace is a
+// single empty part, so the message's picture and attachments are kept
+func splitRunes(text []rune, spans []runeSpan, length int) [][2]int {
+	if length < 1 {
+		length = 1
+	}
+
+	var chunks [][2]int
+	start := skipSpace(text, 0)
+	for start < len(text) {
+		end := len(text)
+		if end-start > length {
+			end = splitPoint(text, spans, start, start+length)
+		}
+
+		trimmed := end
+		for trimmed > start && unicode.IsSpace(text[trimmed-1]) {
+			trimmed--
+		}
+		chunks = append(chunks, [2]int{start, trimmed})
+		start = skipSpace(text, end)
+	}
+	if len(chunks) == 0 {
+		chunks = append(chunks, [2]int{0, 0})
+	}
+	return chunks
+}
+
+func skipSpace(text []rune, i int) int {
+	for i < len(text) && unicode.IsSpace(text[i]) {
+		i++
+	}
+	return i
+}
+
+// splitPoint returns where to end the part starting at start, at most limit:
+// after the last paragraph or sentence, unless that leaves the part less
+// than a quarter full, else after the last word, else at the limit.
+// Never inside a mention
+func splitPoint(text []rune, spans []runeSpan, start, limit int) int {
+	inMention := func(i int) bool {
+		for _, span := range spans {
+			if span.start < i && i < span.end {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The window includes the rune after the limit, so a space
+	// there makes the limit a word boundary
+	window := string(text[start:limit])
+	if limit < len(text) {
+		window = string(text[start : limit+1])
+	}
+	quarter := (limit - start) / 4
+	// Boundaries, as the index in the window of the last rune to keep
+	boundaries := []func() int{
+		func() int { return lastRuneIndex(window, "\n\n", quarter) },
+		func() int { return lastRuneIndex(window, "\n", quarter) },
+		func() int {
+			best := -1
+			for _, end := range []string{". ", "! ", "? "} {
+				// Keep the punctuation
+				if i := lastRuneIndex(window, end, quarter); i >= 0 && i+1 > best {
+					best = i + 1
+				}
+			}
+			return best
+		},
+		func() int { return lastRuneIndex(window, " ", 1) },
+	}
+	for _, boundary := range boundaries {
+		if i := boundary(); i > 0 && !inMention(start+i) {
+			return start + i
+		}
+	}
+
+	// Hard split, before any mention crossing the limit
+	for _, span := range spans {
+		if span.start < limit && limit < span.end && span.start > start {
+			return span.start
+		}
+	}
+	return limit
+}
+
+// lastRuneIndex returns the rune index of the last occurrence of sep in s
+// at or after rune index min, or -1
+func lastRuneIndex(s, sep string, min int) int {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return -1
+	}
+	if runes := len([]rune(s[:i])); runes >= min {
+		return runes
+	}
+	return -1
+}
+
+/*
+SendLong -
+
+Sends the message to the conversation, split by SplitMessage if its
+text is too long. The parts are sent in order, one at a time.
+
+Returns the created messages. On error, those sent before the error
+*/
+func SendLong(ctx context.Context, conversation Conversation, m *Message, options ...SplitOption) ([]*Message, error) {
+	var sent []*Message
+	for _, part := range SplitMessage(m, options...) {
+		msg, err := conversation.Send(ctx, part)
+		if err != nil {
+			return sent, err
+		}
+		sent = append(sent, msg)
+	}
+	return sent, nil
+}
+
+// PostLongBotMessage posts the message's text, picture and attachments
+// from the bot, split by SplitMessage if its text is too long. The parts
+// are posted in order, one at a time
+func (c *BotClient) PostLongBotMessage(ctx context.Context, m *Message, options ...SplitOption) error {
+	for _, part := range SplitMessage(m, options...) {
+		botOptions := []BotMessageOption{WithBotAttachments(part.Attachments...)}
+		if part.ImageURL != "" {
+			botOptions = append(botOptions, WithBotPicture(part.ImageURL))
+		}
+		if err := c.PostBotMessageWithOptions(ctx, part.Text, botOptions...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
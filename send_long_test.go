@@ -0,0 +1,105 @@
+package groupme_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/densestvoid/groupme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func texts(messages []*groupme.Message) []string {
+	var texts []string
+	for _, msg := range messages {
+		texts = append(texts, msg.Text)
+	}
+	return texts
+}
+
+func TestSplitMessage(t *testing.T) {
+	short := &groupme.Message{Text: "short"}
+	assert.Equal(t, []*groupme.Message{short}, groupme.SplitMessage(short))
+
+	parts := groupme.SplitMessage(&groupme.Message{Text: "First paragraph here.\n\nSecond one. It has two sentences."}, groupme.WithSplitLength(30))
+	assert.Equal(t, []string{"First paragraph here.", "Second one.", "It has two sentences."}, texts(parts))
+
+	parts = groupme.SplitMessage(&groupme.Message{Text: "one two three four five six"}, groupme.WithSplitLength(10))
+	assert.Equal(t, []string{"one two", "three four", "five six"}, texts(parts))
+
+	// Text of only whitespace keeps the picture in a single part
+	blank := &groupme.Message{Text: strings.Repeat(" \n", 10), ImageURL: "https://i.groupme.com/1"}
+	parts = groupme.SplitMessage(blank, groupme.WithSplitLength(4))
+	require.Len(t, parts, 1)
+	assert.Empty(t, parts[0].Text)
+	assert.Equal(t, blank.ImageURL, parts[0].ImageURL)
+
+	parts = groupme.SplitMessage(&groupme.Message{Text: "abcdefghij"}, groupme.WithSplitLength(4))
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, texts(parts))
+
+	parts = groupme.SplitMessage(&groupme.Message{Text: "one two three four five six"}, groupme.WithSplitLength(16), groupme.WithSplitNumbering())
+	assert.Equal(t, []string{"one two (1/3)", "three four (2/3)", "five six (3/3)"}, texts(parts))
+	for _, part := range parts {
+		assert.LessOrEqual(t, len(part.Text), 16)
+	}
+}
+
+func TestSplitMessage_Attachments(t *testing.T) {
+	// "😀 hi @Bob" has the mention at UTF-16 offset 6
+	text := "😀 hi @Bob and @Carol Smith, see the picture"
+	msg := &groupme.Message{
+		Text:     text,
+		ImageURL: "https://i.groupme.com/1",
+		Attachments: []*groupme.Attachment{
+			{Type: groupme.Mentions, UserIDs: []string{"2", "3"}, Loci: [][]int{{6, 4}, {15, 12}}},
+			{Type: groupme.Reply, ReplyID: "9", BaseReplyID: "9"},
+		},
+	}
+
+	parts := groupme.SplitMessage(msg, groupme.WithSplitLength(24))
+	require.Equal(t, []string{"😀 hi @Bob and", "@Carol Smith, see the", "picture"}, texts(parts))
+
+	assert.Equal(t, "https://i.groupme.com/1", parts[0].ImageURL)
+	require.Len(t, parts[0].Attachments, 2)
+	assert.Equal(t, groupme.Reply, parts[0].Attachments[0].Type)
+	assert.Equal(t, []string{"2"}, parts[0].MentionedUserIDs())
+	assert.Equal(t, "@Bob", parts[0].MentionSpans()[0].Text)
+
+	assert.Empty(t, parts[1].ImageURL)
+	require.Len(t, parts[1].MentionSpans(), 1)
+	assert.Equal(t, groupme.MentionSpan{UserID: "3", Offset: 0, Length: 12, Text: "@Carol Smith"}, parts[1].MentionSpans()[0])
+
+	assert.Empty(t, parts[2].Attachments)
+	assert.Len(t, msg.Attachments, 2, "the message is left unchanged")
+}
+
+// longText is three paragraphs, each under MaxTextLength
+var longText = strings.TrimSpace(strings.Repeat(strings.Repeat("word ", 150)+"\n\n", 3))
+
+func TestSendLong(t *testing.T) {
+	team := newTeam(t)
+
+	sent, err := groupme.SendLong(context.Background(), team.alice.GroupConversation(team.group), &groupme.Message{Text: longText}, groupme.WithSplitNumbering())
+	require.NoError(t, err)
+	require.Len(t, sent, 3)
+
+	messages := team.server.Messages(team.group.ID)
+	require.Len(t, messages, 3)
+	for i, msg := range messages {
+		assert.Equal(t, sent[i].ID, msg.ID)
+		assert.LessOrEqual(t, len([]rune(msg.Text)), groupme.MaxTextLength)
+	}
+	assert.True(t, strings.HasSuffix(messages[2].Text, "word (3/3)"))
+}
+
+func TestPostLongBotMessage(t *testing.T) {
+	team := newTeam(t)
+	ctx := context.Background()
+
+	bot, err := team.alice.CreateBot(ctx, &groupme.Bot{Name: "Reporter", GroupID: team.group.ID})
+	require.NoError(t, err)
+	botClient := groupme.NewBotClient(bot.BotID, team.server.ClientOptions()...)
+	require.NoError(t, botClient.PostLongBotMessage(ctx, &groupme.Message{Text: longText}))
+	assert.Len(t, team.server.Messages(team.group.ID), 3)
+}
@@ -38,6 +38,10 @@ Parameters:
 		delivered to the device.
 */
 func (c *Client) CreateSMSMode(ctx context.Context, duration int, registrationID *string) error {
+	if err := c.validate(validSMSModeDuration(duration)); err != nil {
+		return err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase + createSMSModeEndpoint)
 
 	var data = struct {
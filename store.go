@@ -0,0 +1,277 @@
+package groupme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrKeyNotFound is returned by Store.Get for keys without a value
+var ErrKeyNotFound = errors.New("groupme: key not found")
+
+// Store persists keyed blobs for long running features, such as Watcher
+// checkpoints, so they survive restarts. Implementations must be safe for
+// concurrent use
+type Store interface {
+	// Get returns the key's value, or ErrKeyNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put sets the key's value
+	Put(ctx context.Context, key string, value []byte) error
+	// Delete removes the key's value, if any
+	Delete(ctx context.Context, key string) error
+	// CompareAndSwap sets the key's value to new only if its current value
+	// is old, reporting whether it did. A nil old value matches a missing key
+	CompareAndSwap(ctx context.Context, key string, old, new []byte) (bool, error)
+}
+
+/*//////// Memory Store ////////*/
+
+// MemoryStore is a Store that lasts as long as the process
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: map[string][]byte{}}
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return copyBytes(value), nil
+}
+
+// Put implements Store
+func (s *MemoryStore) Put(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = copyBytes(value)
+	return nil
+}
+
+// Delete implements Store
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	return nil
+}
+
+// CompareAndSwap implements Store
+func (s *MemoryStore) CompareAndSwap(ctx context.Context, key string, old, new []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !matches(s.values, key, old) {
+		return false, nil
+	}
+	s.values[key] = copyBytes(new)
+	return true, nil
+}
+
+/*//////// File Store ////////*/
+
+// FileStore is a Store saved as a JSON object in a file. Every change
+// rewrites the file atomically, by writing a temporary file and renaming it
+// over the original, so a crash never leaves a partially written store.
+// A file should only be used by one FileStore at a time
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewFileStore opens the store saved at path, which is created on the first change if missing
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, values: map[string][]byte{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.values); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get implements Store
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return copyBytes(value), nil
+}
+
+// Put implements Store
+func (s *FileStore) Put(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.set(key, nonNil(value))
+}
+
+// Delete implements Store
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.values[key]; !ok {
+		return nil
+	}
+	return s.set(key, nil)
+}
+
+// CompareAndSwap implements Store
+func (s *FileStore) CompareAndSwap(ctx context.Context, key string, old, new []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !matches(s.values, key, old) {
+		return false, nil
+	}
+	if err := s.set(key, nonNil(new)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// set changes the value, deleting it if nil, and saves the file.
+// The change is undone if saving fails. Callers must hold the lock
+func (s *FileStore) set(key string, value []byte) error {
+	previous, existed := s.values[key]
+	if value == nil {
+		delete(s.values, key)
+	} else {
+		s.values[key] = copyBytes(value)
+	}
+
+	if err := s.save(); err != nil {
+		if existed {
+			s.values[key] = previous
+		} else {
+			delete(s.values, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) save() error {
+	data, err := json.Marshal(s.values)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+/*//////// Helpers ////////*/
+
+// matches reports whether the key's value is old, or missing if old is nil
+func matches(values map[string][]byte, key string, old []byte) bool {
+	value, ok := values[key]
+	if old == nil {
+		return !ok
+	}
+	return ok && bytes.Equal(value, old)
+}
+
+// nonNil returns b, or an empty value if nil, as set deletes nil values
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+/*//////// Checkpoints ////////*/
+
+// storeCheckpoints is a CheckpointStore keeping checkpoints in a Store
+type storeCheckpoints struct {
+	store  Store
+	prefix string
+}
+
+// NewStoreCheckpointStore keeps Watcher checkpoints in the store, under keys
+// of the prefix followed by the group ID. Checkpoints only move forward, so
+// Watchers sharing a store never redeliver messages
+func NewStoreCheckpointStore(store Store, prefix string) CheckpointStore {
+	return &storeCheckpoints{store: store, prefix: prefix}
+}
+
+// Checkpoint implements CheckpointStore
+func (s *storeCheckpoints) Checkpoint(ctx context.Context, groupID string) (string, error) {
+	value, err := s.store.Get(ctx, s.prefix+groupID)
+	if errors.Is(err, ErrKeyNotFound) {
+		return "", nil
+	}
+	return string(value), err
+}
+
+// SetCheckpoint implements CheckpointStore
+func (s *storeCheckpoints) SetCheckpoint(ctx context.Context, groupID, messageID string) error {
+	key := s.prefix + groupID
+	for {
+		old, err := s.store.Get(ctx, key)
+		if errors.Is(err, ErrKeyNotFound) {
+			old = nil
+		} else if err != nil {
+			return err
+		} else if CompareIDs(string(old), messageID) >= 0 {
+			return nil
+		}
+
+		swapped, err := s.store.CompareAndSwap(ctx, key, old, []byte(messageID))
+		if err != nil || swapped {
+			return err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package groupme
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	_, err := store.Get(ctx, "key")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+
+	swapped, err := store.CompareAndSwap(ctx, "key", []byte("old"), []byte("new"))
+	require.NoError(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = store.CompareAndSwap(ctx, "key", nil, []byte("first"))
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	swapped, err = store.CompareAndSwap(ctx, "key", nil, []byte("again"))
+	require.NoError(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = store.CompareAndSwap(ctx, "key", []byte("first"), []byte("second"))
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	value, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), value)
+
+	// Values are copied
+	value[0] = 'X'
+	value, err = store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), value)
+
+	require.NoError(t, store.Put(ctx, "other", []byte{0, 1, 2}))
+	require.NoError(t, store.Delete(ctx, "key"))
+	_, err = store.Get(ctx, "key")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+	testStore(t, store)
+
+	// Reopening reads the saved values
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	value, err := reopened.Get(context.Background(), "other")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2}, value)
+	_, err = reopened.Get(context.Background(), "key")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{path}, matches, "temporary files are removed")
+}
+
+func TestStoreCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	checkpoints := NewStoreCheckpointStore(store, "watcher/")
+
+	checkpoint, err := checkpoints.Checkpoint(ctx, "1")
+	require.NoError(t, err)
+	assert.Empty(t, checkpoint)
+
+	require.NoError(t, checkpoints.SetCheckpoint(ctx, "1", "100"))
+	// Checkpoints never move backward
+	require.NoError(t, checkpoints.SetCheckpoint(ctx, "1", "99"))
+
+	checkpoint, err = checkpoints.Checkpoint(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "100", checkpoint)
+
+	value, err := store.Get(ctx, "watcher/1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("100"), value)
+}
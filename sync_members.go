@@ -0,0 +1,324 @@
+package groupme
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+/*//////// Roster ////////*/
+
+// ReadRosterCSV reads a roster of members from CSV. The header row names
+// the columns: nickname, and any of user_id, phone_number and email
+func ReadRosterCSV(r io.Reader) ([]*Member, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("roster header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["nickname"]; !ok {
+		return nil, errors.New("roster header has no nickname column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var roster []*Member
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		roster = append(roster, &Member{
+			Nickname:    field(record, "nickname"),
+			UserID:      field(record, "user_id"),
+			PhoneNumber: field(record, "phone_number"),
+			Email:       field(record, "email"),
+		})
+	}
+	return roster, validateRoster(roster)
+}
+
+// ReadRosterJSON reads a roster from a JSON array of members, with the
+// nickname and any of user_id, phone_number and email
+func ReadRosterJSON(r io.Reader) ([]*Member, error) {
+	var roster []*Member
+	if err := json.NewDecoder(r).Decode(&roster); err != nil {
+		return nil, err
+	}
+	return roster, validateRoster(roster)
+}
+
+// validateRoster checks every entry has a nickname and identifier, and no
+// user ID, phone number or email appears twice
+func validateRoster(roster []*Member) error {
+	seen := map[string]bool{}
+	for i, entry := range roster {
+		if entry == nil || entry.Nickname == "" {
+			return fmt.Errorf("roster entry %d has no nickname", i+1)
+		}
+
+		identifiers := []string{"user_id:" + entry.UserID, "phone_number:" + entry.PhoneNumber, "email:" + strings.ToLower(entry.Email)}
+		var found bool
+		for _, identifier := range identifiers {
+			if strings.HasSuffix(identifier, ":") {
+				continue
+			}
+			if seen[identifier] {
+				return fmt.Errorf("roster entry %d duplicates %s", i+1, identifier)
+			}
+			seen[identifier] = true
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("roster entry %d (%s) has no user ID, phone number or email", i+1, entry.Nickname)
+		}
+	}
+	return nil
+}
+
+/*//////// Plan ////////*/
+
+// MemberSyncPlan is the changes converging a group's members to a roster
+type MemberSyncPlan struct {
+	GroupID string
+	// Add holds the roster entries without a membership
+	Add []*Member
+	// Remove holds the memberships not in the roster
+	Remove []*Member
+	// Keep holds the memberships in the roster
+	Keep []*Member
+	// Protected holds the memberships not in the roster that are never
+	// removed, such as the group creator's
+	Protected []*Member
+	// MatchedByNickname is set when a roster entry has no user ID. A member
+	// whose nickname differs from their entry is then planned for removal,
+	// and their entry for adding
+	MatchedByNickname bool
+}
+
+/*
+PlanMemberSync -
+
+Compares the group's members to the roster. Roster entries with a user ID
+match the member with that user ID. The API doesn't reveal members' phone
+numbers or emails, so other entries match a member with the same nickname,
+ignoring case, and set MatchedByNickname.
+
+The creator and the protected user IDs are never removed. Nicknames
+aren't changed, as only members can change their own
+*/
+func PlanMemberSync(group *Group, roster []*Member, protectedUserIDs ...string) *MemberSyncPlan {
+	plan := &MemberSyncPlan{GroupID: group.ID}
+
+	byUserID := map[string]*Member{}
+	byNickname := map[string]*Member{}
+	for _, member := range group.Members {
+		byUserID[member.UserID] = member
+		byNickname[strings.ToLower(member.Nickname)] = member
+	}
+
+	kept := map[string]bool{}
+	for _, entry := range roster {
+		member, ok := byUserID[entry.UserID]
+		if entry.UserID == "" {
+			plan.MatchedByNickname = true
+			member, ok = byNickname[strings.ToLower(entry.Nickname)]
+		}
+		if !ok || kept[member.ID] {
+			plan.Add = append(plan.Add, entry)
+			continue
+		}
+		kept[member.ID] = true
+		plan.Keep = append(plan.Keep, member)
+	}
+
+	protected := map[string]bool{group.CreatorUserID: true}
+	for _, userID := range protectedUserIDs {
+		protected[userID] = true
+	}
+	for _, member := range group.Members {
+		switch {
+		case kept[member.ID]:
+		case protected[member.UserID]:
+			plan.Protected = append(plan.Protected, member)
+		default:
+			plan.Remove = append(plan.Remove, member)
+		}
+	}
+	return plan
+}
+
+/*//////// Sync ////////*/
+
+// MemberSyncReport is the outcome of SyncMembers
+type MemberSyncReport struct {
+	Plan   *MemberSyncPlan
+	DryRun bool
+	// Adds reports which members were added. Nil if there were none, or on a dry run
+	Adds *AddMembersReport
+	// Removed holds the memberships removed, in the order of Plan.Remove
+	Removed []*Member
+}
+
+// ErrUnmatchedRemoval is returned by SyncMembers for a roster with entries
+// matched by nickname, when the plan removes members
+var ErrUnmatchedRemoval = errors.New("groupme: roster entries matched members by nickname, refusing to remove members")
+
+type memberSync struct {
+	dryRun          bool
+	delay           time.Duration
+	addTimeout      time.Duration
+	protected       []string
+	removeUnmatched bool
+}
+
+// MemberSyncOption configures SyncMembers
+type MemberSyncOption func(*memberSync)
+
+// WithSyncDryRun plans the sync without changing the group
+func WithSyncDryRun() MemberSyncOption {
+	return func(s *memberSync) {
+		s.dryRun = true
+	}
+}
+
+// WithSyncDelay sets the pause between the requests changing the group,
+// to spread out large syncs. Defaults to 1 second
+func WithSyncDelay(delay time.Duration) MemberSyncOption {
+	return func(s *memberSync) {
+		s.delay = delay
+	}
+}
+
+// WithSyncAddTimeout sets how long to wait for the results of the adds. Defaults to 1 minute
+func WithSyncAddTimeout(timeout time.Duration) MemberSyncOption {
+	return func(s *memberSync) {
+		s.addTimeout = timeout
+	}
+}
+
+// WithSyncRemoveUnmatched removes the members not in the roster even when
+// roster entries without a user ID were matched by nickname, so a member
+// whose nickname differs from their entry is removed and added again
+func WithSyncRemoveUnmatched() MemberSyncOption {
+	return func(s *memberSync) {
+		s.removeUnmatched = true
+	}
+}
+
+// WithSyncProtect protects the users from removal, in addition to the
+// group creator and the client's user
+func WithSyncProtect(userIDs ...string) MemberSyncOption {
+	return func(s *memberSync) {
+		s.protected = append(s.protected, userIDs...)
+	}
+}
+
+/*
+SyncMembers -
+
+Converges the group's members to the roster, as planned by PlanMemberSync:
+adds the missing roster entries with AddMembersAndWait, then removes the
+members not in the roster one at a time. The group creator and the
+client's own user are never removed.
+
+If the plan removes members and a roster entry has no user ID, nothing is
+changed and ErrUnmatchedRemoval is returned, unless WithSyncRemoveUnmatched
+is given. Dry runs return the plan either way.
+
+On error, the report holds the changes made so far
+*/
+func (c *Client) SyncMembers(ctx context.Context, groupID string, roster []*Member, options ...MemberSyncOption) (*MemberSyncReport, error) {
+	s := memberSync{delay: time.Second, addTimeout: time.Minute}
+	for _, option := range options {
+		option(&s)
+	}
+
+	if err := validateRoster(roster); err != nil {
+		return nil, err
+	}
+
+	group, err := c.ShowGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	me, err := c.MyUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MemberSyncReport{
+		Plan:   PlanMemberSync(group, roster, append(s.protected, me.ID)...),
+		DryRun: s.dryRun,
+	}
+	if s.dryRun {
+		return report, nil
+	}
+	if report.Plan.MatchedByNickname && len(report.Plan.Remove) > 0 && !s.removeUnmatched {
+		return report, ErrUnmatchedRemoval
+	}
+
+	if len(report.Plan.Add) > 0 {
+		// Copy the entries, so assigning GUIDs doesn't change the roster
+		members := make([]*Member, len(report.Plan.Add))
+		for i, entry := range report.Plan.Add {
+			member := *entry
+			members[i] = &member
+		}
+
+		report.Adds, err = c.AddMembersAndWait(ctx, groupID, s.addTimeout, members...)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	for i, member := range report.Plan.Remove {
+		if i > 0 || report.Adds != nil {
+			if err := sleep(ctx, s.delay); err != nil {
+				return report, err
+			}
+		}
+
+		if err := c.RemoveMember(ctx, groupID, member.ID); err != nil {
+			return report, fmt.Errorf("removing %s: %w", member.Nickname, err)
+		}
+		report.Removed = append(report.Removed, member)
+	}
+	return report, nil
+}
+
+// sleep waits for the duration, or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package groupme_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRoster(t *testing.T) {
+	roster, err := groupme.ReadRosterCSV(strings.NewReader("Nickname, email, user_id\nBob,,2\nCarol, carol@example.com,\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []*groupme.Member{
+		{Nickname: "Bob", UserID: "2"},
+		{Nickname: "Carol", Email: "carol@example.com"},
+	}, roster)
+
+	roster, err = groupme.ReadRosterJSON(strings.NewReader(`[{"nickname": "Dave", "phone_number": "+1 5555555555"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []*groupme.Member{{Nickname: "Dave", PhoneNumber: "+1 5555555555"}}, roster)
+
+	invalid := []string{
+		"user_id\n2\n",
+		"nickname,user_id\nBob,\n",
+		"nickname,user_id\n,2\n",
+		"nickname,email\nBob,bob@example.com\nRobert,BOB@example.com\n",
+	}
+	for _, csv := range invalid {
+		_, err := groupme.ReadRosterCSV(strings.NewReader(csv))
+		assert.Error(t, err, csv)
+	}
+}
+
+func TestPlanMemberSync(t *testing.T) {
+	group := &groupme.Group{
+		ID:            "1",
+		CreatorUserID: "1",
+		Members: []*groupme.Member{
+			{ID: "m1", UserID: "1", Nickname: "Alice"},
+			{ID: "m2", UserID: "2", Nickname: "Bob"},
+			{ID: "m3", UserID: "3", Nickname: "Carol"},
+			{ID: "m4", UserID: "4", Nickname: "Dave"},
+			{ID: "m5", UserID: "5", Nickname: "Eve"},
+		},
+	}
+	roster := []*groupme.Member{
+		{Nickname: "Bobby", UserID: "2"},
+		{Nickname: "carol", Email: "carol@example.com"},
+		{Nickname: "Frank", UserID: "6"},
+	}
+
+	plan := groupme.PlanMemberSync(group, roster, "5")
+	assert.Equal(t, []*groupme.Member{roster[2]}, plan.Add)
+	assert.Equal(t, []*groupme.Member{group.Members[1], group.Members[2]}, plan.Keep)
+	assert.Equal(t, []*groupme.Member{group.Members[3]}, plan.Remove)
+	assert.Equal(t, []*groupme.Member{group.Members[0], group.Members[4]}, plan.Protected)
+	assert.True(t, plan.MatchedByNickname)
+
+	plan = groupme.PlanMemberSync(group, roster[:1])
+	assert.False(t, plan.MatchedByNickname)
+}
+
+// syncRoster adds Carol, who is matched by nickname, and a user who doesn't exist
+func syncRoster() []*groupme.Member {
+	return []*groupme.Member{
+		{Nickname: "Carol", Email: "carol@example.com"},
+		{Nickname: "Nobody", UserID: "404"},
+	}
+}
+
+func TestSyncMembers_DryRun(t *testing.T) {
+	team := newTeam(t, "2")
+
+	report, err := team.alice.SyncMembers(context.Background(), team.group.ID, syncRoster(), groupme.WithSyncDryRun())
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.Plan.Add, 2)
+	assert.Len(t, report.Plan.Remove, 1)
+	assert.Nil(t, report.Adds)
+	assert.Len(t, team.server.Group(team.group.ID).Members, 2)
+}
+
+func TestSyncMembers_UnmatchedRemoval(t *testing.T) {
+	team := newTeam(t, "2")
+
+	// Carol's entry is matched by nickname, so Bob might be her
+	report, err := team.alice.SyncMembers(context.Background(), team.group.ID, syncRoster(), groupme.WithSyncDelay(0))
+	assert.ErrorIs(t, err, groupme.ErrUnmatchedRemoval)
+	assert.Len(t, report.Plan.Remove, 1)
+	assert.Len(t, team.server.Group(team.group.ID).Members, 2)
+}
+
+func TestSyncMembers(t *testing.T) {
+	team := newTeam(t, "2")
+	roster := syncRoster()
+
+	report, err := team.alice.SyncMembers(context.Background(), team.group.ID, roster, groupme.WithSyncDelay(0), groupme.WithSyncAddTimeout(time.Second), groupme.WithSyncRemoveUnmatched())
+	require.NoError(t, err)
+	require.NotNil(t, report.Adds)
+	assert.Len(t, report.Adds.Added(), 1)
+	require.Len(t, report.Adds.Failed(), 1)
+	assert.Equal(t, "Nobody", report.Adds.Failed()[0].Nickname)
+	assert.Empty(t, roster[0].GUID, "the roster is left unchanged")
+	require.Len(t, report.Removed, 1)
+	assert.Equal(t, "2", report.Removed[0].UserID)
+
+	var userIDs []string
+	for _, member := range team.server.Group(team.group.ID).Members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	assert.ElementsMatch(t, []string{"1", "3"}, userIDs)
+}
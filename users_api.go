@@ -70,6 +70,10 @@ UpdateMyUser -
 Parameters: See UserSettings
 */
 func (c *Client) UpdateMyUser(ctx context.Context, us UserSettings) (*User, error) {
+	if err := c.validate(us.validate); err != nil {
+		return nil, err
+	}
+
 	URL := fmt.Sprintf(c.apiEndpointBase + updateMyUserEndpoint)
 
 	jsonBytes, err := json.Marshal(&us)
@@ -0,0 +1,283 @@
+package groupme
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Documented limits of the fields checked by validation
+const (
+	MaxGroupNameLength        = 140
+	MaxGroupDescriptionLength = 255
+	MaxNicknameLength         = 50
+	MaxSMSModeDuration        = 48
+)
+
+// ImageServiceHost is the host of the URLs of pictures uploaded to the image service
+const ImageServiceHost = "i.groupme.com"
+
+// FieldError is a field violating a documented constraint
+type FieldError struct {
+	// JSON name of the field, e.g. "name" or "attachments[0].url"
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError is returned, without making a request, for arguments
+// violating the documented constraints. It lists every violated field
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error returns the violated fields as a string.
+// Satisfies the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "groupme: invalid " + strings.Join(messages, "; ")
+}
+
+// Field returns the error of the field, or nil
+func (e *ValidationError) Field(field string) *FieldError {
+	for i := range e.Errors {
+		if e.Errors[i].Field == field {
+			return &e.Errors[i]
+		}
+	}
+	return nil
+}
+
+// WithValidation checks the arguments of requests against the documented
+// constraints before sending them, returning a *ValidationError instead
+// of making a request. Defaults to leaving the checks to GroupMe.
+//
+// Picture URLs on the host of an image service set with WithImageBaseURL,
+// such as a local stand-in, are accepted as well as those on ImageServiceHost
+func WithValidation() ClientOption {
+	return func(client *client) {
+		client.validation = true
+	}
+}
+
+// validator collects the violated fields
+type validator struct {
+	errors []FieldError
+	// imageHost is accepted in picture URLs besides ImageServiceHost
+	imageHost string
+}
+
+// check records the field as violated unless ok
+func (v *validator) check(ok bool, field, format string, args ...interface{}) {
+	if !ok {
+		v.errors = append(v.errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+func (v *validator) length(field, value string, min, max int) {
+	n := utf8.RuneCountInString(value)
+	switch {
+	case n < min && min == 1:
+		v.check(false, field, "required")
+	case n < min:
+		v.check(false, field, "shorter than %d characters", min)
+	case n > max:
+		v.check(false, field, "longer than %d characters", max)
+	}
+}
+
+func (v *validator) id(field, id string) {
+	v.check(ValidID(id), field, "%q is not a valid ID", id)
+}
+
+// optionalID checks the ID only if it is set
+func (v *validator) optionalID(field, id string) {
+	if id != "" {
+		v.id(field, id)
+	}
+}
+
+// imageURL checks the URL, if set, is an image service URL
+func (v *validator) imageURL(field, rawURL string) {
+	if rawURL == "" {
+		return
+	}
+	u, err := url.Parse(rawURL)
+	v.check(err == nil && (u.Host == ImageServiceHost || (v.imageHost != "" && u.Host == v.imageHost)), field, "%q is not an image service URL (%s)", rawURL, ImageServiceHost)
+}
+
+// absoluteURL checks the URL, if set, is an absolute http or https URL
+func (v *validator) absoluteURL(field, rawURL string) {
+	if rawURL == "" {
+		return
+	}
+	u, err := url.Parse(rawURL)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", field, "%q is not an http or https URL", rawURL)
+}
+
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+// validate runs the checks if the client has validation enabled
+func (c *client) validate(checks ...func(v *validator)) error {
+	if !c.validation {
+		return nil
+	}
+
+	var v validator
+	if c.imageEndpointBase != GroupMeImageBase {
+		if u, err := url.Parse(c.imageEndpointBase); err == nil {
+			v.imageHost = u.Host
+		}
+	}
+	return v.run(checks...)
+}
+
+func validate(checks ...func(v *validator)) error {
+	var v validator
+	return v.run(checks...)
+}
+
+func (v *validator) run(checks ...func(v *validator)) error {
+	for _, check := range checks {
+		check(v)
+	}
+	return v.err()
+}
+
+func validID(field, id string) func(v *validator) {
+	return func(v *validator) {
+		v.id(field, id)
+	}
+}
+
+func validNickname(nickname string) func(v *validator) {
+	return func(v *validator) {
+		v.length("nickname", nickname, 1, MaxNicknameLength)
+	}
+}
+
+func validSMSModeDuration(duration int) func(v *validator) {
+	return func(v *validator) {
+		v.check(duration >= 1 && duration <= MaxSMSModeDuration, "duration", "%d is not between 1 and %d hours", duration, MaxSMSModeDuration)
+	}
+}
+
+/*//////// Validate ////////*/
+
+// Validate checks the settings against the documented constraints
+func (gss GroupSettings) Validate() error {
+	return validate(gss.validate)
+}
+
+func (gss GroupSettings) validate(v *validator) {
+	v.length("name", gss.Name, 1, MaxGroupNameLength)
+	v.length("description", gss.Description, 0, MaxGroupDescriptionLength)
+	v.imageURL("image_url", gss.ImageURL)
+}
+
+// Validate checks the settings against the documented constraints
+func (us UserSettings) Validate() error {
+	return validate(us.validate)
+}
+
+func (us UserSettings) validate(v *validator) {
+	// Any picture URL is converted into an image service URL
+	v.absoluteURL("avatar_url", us.AvatarURL)
+	if us.Name != "" {
+		v.check(len(strings.Fields(us.Name)) >= 2, "name", "%q is not of the form FirstName LastName", us.Name)
+	}
+	if us.Email != "" {
+		at := strings.LastIndex(us.Email, "@")
+		v.check(at > 0 && strings.Contains(us.Email[at+1:], "."), "email", "%q is not of the form name@domain.com", us.Email)
+	}
+}
+
+// Validate checks the message to be sent against the documented constraints
+func (m *Message) Validate() error {
+	return validate(m.validate)
+}
+
+func (m *Message) validate(v *validator) {
+	v.check(m.Text != "" || m.ImageURL != "" || len(m.Attachments) > 0, "text", "required without a picture or attachments")
+	v.length("text", m.Text, 0, MaxTextLength)
+	v.imageURL("image_url", m.ImageURL)
+
+	for i, attachment := range m.Attachments {
+		field := fmt.Sprintf("attachments[%d]", i)
+		if attachment == nil {
+			v.check(false, field, "nil")
+			continue
+		}
+
+		switch attachment.Type {
+		case Image:
+			v.check(attachment.URL != "", field+".url", "required")
+			v.imageURL(field+".url", attachment.URL)
+		case Mentions:
+			v.check(len(attachment.Loci) == len(attachment.UserIDs), field+".loci", "%d loci for %d user IDs", len(attachment.Loci), len(attachment.UserIDs))
+			for j, locus := range attachment.Loci {
+				v.check(len(locus) == 2 && locus[0] >= 0 && locus[1] > 0, fmt.Sprintf("%s.loci[%d]", field, j), "%v is not an [offset, length]", locus)
+			}
+		case Reply:
+			v.id(field+".reply_id", attachment.ReplyID)
+		}
+	}
+}
+
+// Validate checks the member to be added against the documented constraints
+func (m *Member) Validate() error {
+	return validate(m.validate)
+}
+
+func (m *Member) validate(v *validator) {
+	validNickname(m.Nickname)(v)
+	v.check(m.UserID != "" || m.PhoneNumber != "" || m.Email != "", "user_id", "one of user_id, phone_number or email is required")
+	v.optionalID("user_id", m.UserID)
+	if m.PhoneNumber != "" {
+		v.check(PhoneNumber(m.PhoneNumber).Valid(), "phone_number", "%q is not of the form +1 5555555555", m.PhoneNumber)
+	}
+}
+
+// validMembers checks each of the members to be added, prefixing their fields
+func validMembers(members []*Member) func(v *validator) {
+	return func(v *validator) {
+		v.check(len(members) > 0, "members", "required")
+		for i, member := range members {
+			field := fmt.Sprintf("members[%d]", i)
+			if member == nil {
+				v.check(false, field, "nil")
+				continue
+			}
+
+			var mv validator
+			member.validate(&mv)
+			for _, err := range mv.errors {
+				v.errors = append(v.errors, FieldError{Field: field + "." + err.Field, Message: err.Message})
+			}
+		}
+	}
+}
+
+// Validate checks the bot to be created against the documented constraints
+func (b *Bot) Validate() error {
+	return validate(b.validate)
+}
+
+func (b *Bot) validate(v *validator) {
+	v.check(b.Name != "", "name", "required")
+	v.id("group_id", b.GroupID)
+	v.absoluteURL("avatar_url", b.AvatarURL)
+	v.absoluteURL("callback_url", b.CallbackURL)
+}
@@ -0,0 +1,112 @@
+package groupme
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// invalidFields returns the fields listed by the ValidationError
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "%v is not a ValidationError", err)
+
+	var fields []string
+	for _, fieldErr := range validationErr.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, GroupSettings{Name: "Team", ImageURL: "https://i.groupme.com/123"}.Validate())
+	assert.Equal(t, []string{"name", "description", "image_url"}, invalidFields(t, GroupSettings{
+		Description: strings.Repeat("a", MaxGroupDescriptionLength+1),
+		ImageURL:    "https://example.com/123",
+	}.Validate()))
+	assert.Equal(t, []string{"name"}, invalidFields(t, GroupSettings{Name: strings.Repeat("é", MaxGroupNameLength+1)}.Validate()))
+
+	assert.NoError(t, UserSettings{Name: "Alice Smith", Email: "alice@example.com", AvatarURL: "https://example.com/a.png"}.Validate())
+	assert.Equal(t, []string{"avatar_url", "name", "email"}, invalidFields(t, UserSettings{Name: "Alice", Email: "alice", AvatarURL: "a.png"}.Validate()))
+
+	assert.NoError(t, (&Message{Text: strings.Repeat("a", MaxTextLength)}).Validate())
+	assert.NoError(t, (&Message{ImageURL: "https://i.groupme.com/123"}).Validate())
+	assert.Equal(t, []string{"text"}, invalidFields(t, (&Message{}).Validate()))
+	assert.Equal(t, []string{"text", "image_url", "attachments[0].url", "attachments[1].loci", "attachments[1].loci[0]", "attachments[2].reply_id"}, invalidFields(t, (&Message{
+		Text:     strings.Repeat("a", MaxTextLength+1),
+		ImageURL: "https://example.com/123",
+		Attachments: []*Attachment{
+			{Type: Image, URL: "https://example.com/123"},
+			{Type: Mentions, UserIDs: []string{"1", "2"}, Loci: [][]int{{0}}},
+			{Type: Reply},
+		},
+	}).Validate()))
+
+	assert.NoError(t, (&Member{Nickname: "Bob", PhoneNumber: "+1 5555555555"}).Validate())
+	assert.Equal(t, []string{"nickname", "user_id"}, invalidFields(t, (&Member{}).Validate()))
+	assert.Equal(t, []string{"nickname", "user_id", "phone_number"}, invalidFields(t, (&Member{
+		Nickname:    strings.Repeat("a", MaxNicknameLength+1),
+		UserID:      "not valid",
+		PhoneNumber: "5555555555",
+	}).Validate()))
+
+	assert.NoError(t, (&Bot{Name: "Reporter", GroupID: "1", CallbackURL: "https://example.com/callback"}).Validate())
+	assert.Equal(t, []string{"name", "group_id", "callback_url"}, invalidFields(t, (&Bot{CallbackURL: "example.com"}).Validate()))
+}
+
+func TestValidationError(t *testing.T) {
+	err := GroupSettings{Description: strings.Repeat("a", MaxGroupDescriptionLength+1)}.Validate()
+	assert.EqualError(t, err, "groupme: invalid name: required; description: longer than 255 characters")
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.NotNil(t, validationErr.Field("description"))
+	assert.Equal(t, "longer than 255 characters", validationErr.Field("description").Message)
+	assert.Nil(t, validationErr.Field("image_url"))
+}
+
+func TestWithValidation(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"response": {}, "meta": {"code": 200}}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	client := NewClient("token", WithAPIBaseURL(server.URL), WithValidation())
+	_, err := client.CreateGroup(ctx, GroupSettings{})
+	assert.Equal(t, []string{"name"}, invalidFields(t, err))
+	_, err = client.UpdateGroup(ctx, "not valid", GroupSettings{Name: "Team"})
+	assert.Equal(t, []string{"group_id"}, invalidFields(t, err))
+	_, err = client.UpdateMember(ctx, "1", "")
+	assert.Equal(t, []string{"nickname"}, invalidFields(t, err))
+	_, err = client.AddMembers(ctx, "1", &Member{Nickname: "Bob", UserID: "2"}, &Member{Nickname: "Carol"})
+	assert.Equal(t, []string{"members[1].user_id"}, invalidFields(t, err))
+	_, err = client.CreateMessage(ctx, "1", &Message{Text: strings.Repeat("a", MaxTextLength+1)})
+	assert.Equal(t, []string{"text"}, invalidFields(t, err))
+	_, err = client.CreateDirectMessage(ctx, &Message{Text: "hi"})
+	assert.Equal(t, []string{"recipient_id"}, invalidFields(t, err))
+	assert.Equal(t, []string{"duration"}, invalidFields(t, client.CreateSMSMode(ctx, MaxSMSModeDuration+1, nil)))
+
+	botClient := NewBotClient("bot-id", WithAPIBaseURL(server.URL), WithValidation())
+	assert.Equal(t, []string{"image_url"}, invalidFields(t, botClient.PostBotMessageWithOptions(ctx, "hi", WithBotPicture("https://example.com/1"))))
+	assert.Zero(t, atomic.LoadInt32(&calls), "invalid requests aren't sent")
+
+	_, err = client.CreateGroup(ctx, GroupSettings{Name: "Team"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	// Without the option, the checks are left to GroupMe
+	_, err = NewClient("token", WithAPIBaseURL(server.URL)).CreateGroup(ctx, GroupSettings{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,241 @@
+package groupme
+
+import (
+	"context"
+	"time"
+)
+
+// CheckpointStore persists the ID of the last message a Watcher delivered for each group
+type CheckpointStore interface {
+	// Checkpoint returns the group's last delivered message ID, or "" if there is none
+	Checkpoint(ctx context.Context, groupID string) (string, error)
+	// SetCheckpoint records the group's last delivered message ID
+	SetCheckpoint(ctx context.Context, groupID, messageID string) error
+}
+
+/*//////// Watcher ////////*/
+
+// Watcher polls groups for new messages, as an alternative to bot callbacks
+// or the push service. Each poll lists the groups, and only fetches messages
+// from those whose last message is newer than their checkpoint.
+//
+//	watcher := client.NewWatcher(groupme.WithWatchGroups(groupID))
+//	go watcher.Run(ctx)
+//	for msg := range watcher.Messages() {
+//		...
+//	}
+type Watcher struct {
+	client       *Client
+	groupIDs     map[string]bool
+	minInterval  time.Duration
+	maxInterval  time.Duration
+	checkpoints  CheckpointStore
+	errorHandler func(groupID string, err error)
+	messages     chan *Message
+}
+
+// WatcherOption configures a Watcher
+type WatcherOption func(*Watcher)
+
+// WithWatchGroups limits the Watcher to the groups. Defaults to every group the user is in
+func WithWatchGroups(groupIDs ...string) WatcherOption {
+	return func(w *Watcher) {
+		w.groupIDs = map[string]bool{}
+		for _, groupID := range groupIDs {
+			w.groupIDs[groupID] = true
+		}
+	}
+}
+
+// WithPollInterval sets the bounds of the poll interval. The Watcher polls
+// at the minimum interval while there are new messages, doubling the interval
+// up to the maximum while there are none. Defaults to 2 seconds and 1 minute.
+// Intervals of zero or less are ignored, and a maximum below the minimum is raised to it
+func WithPollInterval(min, max time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if min > 0 {
+			w.minInterval = min
+		}
+		if max > 0 {
+			w.maxInterval = max
+		}
+		if w.maxInterval < w.minInterval {
+			w.maxInterval = w.minInterval
+		}
+	}
+}
+
+// WithCheckpointStore persists checkpoints in the store, such as one from
+// NewStoreCheckpointStore, so a restarted Watcher continues where it stopped.
+// Defaults to checkpoints in a MemoryStore
+func WithCheckpointStore(store CheckpointStore) WatcherOption {
+	return func(w *Watcher) {
+		w.checkpoints = store
+	}
+}
+
+// WithWatchErrorHandler sets the function called with API errors, which are
+// retried on the next poll. The group ID is empty for group listing errors
+func WithWatchErrorHandler(handler func(groupID string, err error)) WatcherOption {
+	return func(w *Watcher) {
+		w.errorHandler = handler
+	}
+}
+
+// WithWatchBuffer sets how many messages are buffered before polling
+// waits for the receiver. Defaults to 0
+func WithWatchBuffer(size int) WatcherOption {
+	return func(w *Watcher) {
+		w.messages = make(chan *Message, size)
+	}
+}
+
+// NewWatcher creates a Watcher of the client's groups
+func (c *Client) NewWatcher(options ...WatcherOption) *Watcher {
+	w := &Watcher{
+		client:       c,
+		minInterval:  2 * time.Second,
+		maxInterval:  time.Minute,
+		checkpoints:  NewStoreCheckpointStore(NewMemoryStore(), ""),
+		errorHandler: func(string, error) {},
+		messages:     make(chan *Message),
+	}
+
+	for _, option := range options {
+		option(w)
+	}
+
+	return w
+}
+
+// Messages returns the channel new messages are delivered on, oldest first
+// within each group. It is closed when Run returns
+func (w *Watcher) Messages() <-chan *Message {
+	return w.messages
+}
+
+// Run polls until the context is done, returning its error, or until a
+// checkpoint can't be loaded or saved. Groups without a checkpoint start
+// from their latest message, rather than replaying their history
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.messages)
+
+	interval := w.minInterval
+	for {
+		active, err := w.poll(ctx)
+		if err != nil {
+			return err
+		}
+
+		if active {
+			interval = w.minInterval
+		} else if interval *= 2; interval > w.maxInterval {
+			interval = w.maxInterval
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// poll delivers the new messages of every watched group,
+// reporting whether there were any
+func (w *Watcher) poll(ctx context.Context) (bool, error) {
+	groups, err := w.listGroups(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		w.errorHandler("", err)
+		return false, nil
+	}
+
+	active := false
+	for _, group := range groups {
+		lastMessageID := group.Messages.LastMessageID
+
+		checkpoint, err := w.checkpoints.Checkpoint(ctx, group.ID)
+		if err != nil {
+			return false, err
+		}
+		if checkpoint == "" {
+			if lastMessageID != "" {
+				if err := w.checkpoints.SetCheckpoint(ctx, group.ID, lastMessageID); err != nil {
+					return false, err
+				}
+			}
+			continue
+		}
+		if lastMessageID == "" || CompareIDs(lastMessageID, checkpoint) <= 0 {
+			continue
+		}
+
+		active = true
+		if err := w.deliver(ctx, group.ID, checkpoint); err != nil {
+			return false, err
+		}
+	}
+
+	return active, nil
+}
+
+// deliver sends the group's messages after the checkpoint, saving the checkpoint
+// after each. Only context and checkpoint errors are returned
+func (w *Watcher) deliver(ctx context.Context, groupID, checkpoint string) error {
+	iter := w.client.IterateMessages(groupID, &MessageIteratorOptions{
+		Direction: IterateForward,
+		StartID:   checkpoint,
+	})
+	for iter.Next(ctx) {
+		msg := iter.Message()
+		// Pages may overlap the checkpoint if messages were deleted
+		if CompareIDs(msg.ID, checkpoint) <= 0 {
+			continue
+		}
+
+		select {
+		case w.messages <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		checkpoint = msg.ID
+		if err := w.checkpoints.SetCheckpoint(ctx, groupID, checkpoint); err != nil {
+			return err
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		w.errorHandler(groupID, err)
+	}
+	return nil
+}
+
+// listGroups lists the watched groups, with their last message IDs
+func (w *Watcher) listGroups(ctx context.Context) ([]*Group, error) {
+	var watched []*Group
+	for page := 1; ; page++ {
+		groups, err := w.client.IndexGroups(ctx, &GroupsQuery{Page: page, PerPage: 100, Omit: "memberships"})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			if w.groupIDs == nil || w.groupIDs[group.ID] {
+				watched = append(watched, group)
+			}
+		}
+
+		if len(groups) < 100 {
+			return watched, nil
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package groupme_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/groupme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	team := newTeam(t)
+	server, client, watched := team.server, team.alice, team.group
+	ignored := server.AddGroup("1", groupme.GroupSettings{Name: "Ignored"})
+	history := server.AddMessage(watched.ID, "1", "History")
+
+	store := groupme.NewStoreCheckpointStore(groupme.NewMemoryStore(), "")
+	run := func(texts ...string) []string {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		watcher := client.NewWatcher(
+			groupme.WithWatchGroups(watched.ID),
+			groupme.WithPollInterval(5*time.Millisecond, 20*time.Millisecond),
+			groupme.WithCheckpointStore(store),
+		)
+		done := make(chan error)
+		go func() { done <- watcher.Run(ctx) }()
+
+		// Wait for the first poll to checkpoint the group
+		require.Eventually(t, func() bool {
+			checkpoint, _ := store.Checkpoint(ctx, watched.ID)
+			return checkpoint != ""
+		}, 5*time.Second, time.Millisecond)
+
+		for _, text := range texts {
+			server.AddMessage(watched.ID, "1", text)
+			server.AddMessage(ignored.ID, "1", text)
+		}
+
+		var received []string
+		for len(received) < len(texts) {
+			select {
+			case msg := <-watcher.Messages():
+				assert.Equal(t, watched.ID, msg.GroupID)
+				received = append(received, msg.Text)
+			case <-time.After(5 * time.Second):
+				require.FailNow(t, "timed out waiting for messages")
+			}
+		}
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+		return received
+	}
+
+	assert.Equal(t, []string{"One", "Two", "Three"}, run("One", "Two", "Three"))
+
+	// A restarted Watcher continues from the checkpoint
+	checkpoint, err := store.Checkpoint(context.Background(), watched.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, history.ID, checkpoint)
+	assert.Equal(t, []string{"Four"}, run("Four"))
+}